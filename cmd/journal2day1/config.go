@@ -0,0 +1,194 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/kpod13/journal2day1/internal/converter"
+)
+
+// Sentinel errors for the config file.
+var (
+	errInvalidJournal = errors.New("journal names must not be empty or contain path separators")
+	errInvalidRoute   = errors.New("invalid route")
+	errInvalidTagRule = errors.New("invalid tag rule")
+	errEmptyTag       = errors.New("tags must not be empty")
+)
+
+// fileConfig is the JSON config file given with --config.
+type fileConfig struct {
+	Routes []routeConfig   `json:"routes"`
+	Tags   []tagRuleConfig `json:"tags"`
+}
+
+// routeConfig sends matching entries to a journal, see converter.Route.
+type routeConfig struct {
+	Journal  string   `json:"journal"`
+	Years    []int    `json:"years"`
+	Hashtags []string `json:"hashtags"`
+	Match    string   `json:"match"`
+	HasMedia bool     `json:"hasMedia"`
+}
+
+// tagRuleConfig tags matching entries, see converter.TagRule. Both dates are
+// inclusive.
+type tagRuleConfig struct {
+	Tag        string   `json:"tag"`
+	Keywords   []string `json:"keywords"`
+	Match      string   `json:"match"`
+	AssetTypes []string `json:"assetTypes"`
+	Since      string   `json:"since"`
+	Until      string   `json:"until"`
+}
+
+func loadConfig(path string) (*fileConfig, error) {
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read config")
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+
+	var cfg fileConfig
+	if err := decoder.Decode(&cfg); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse config %s", path)
+	}
+
+	return &cfg, nil
+}
+
+// applyConfigFile loads the config file, if any, and sets its routes and tag
+// rules on the converter.
+func applyConfigFile(conv *converter.Converter, path string) error {
+	if path == "" {
+		return nil
+	}
+
+	fileCfg, err := loadConfig(path)
+	if err != nil {
+		return err
+	}
+
+	routes, err := fileCfg.routes()
+	if err != nil {
+		return err
+	}
+
+	tagRules, err := fileCfg.tagRules()
+	if err != nil {
+		return err
+	}
+
+	conv.SetRoutes(routes)
+	conv.SetTagRules(tagRules)
+
+	return nil
+}
+
+// routes turns the configured routes into converter routes, in file order.
+func (f *fileConfig) routes() ([]converter.Route, error) {
+	routes := make([]converter.Route, 0, len(f.Routes))
+
+	for i, rc := range f.Routes {
+		if err := validateJournalName(rc.Journal); err != nil {
+			return nil, errors.Wrapf(errInvalidRoute, "route %d: %v", i+1, err)
+		}
+
+		route := converter.Route{
+			Journal:  rc.Journal,
+			Years:    rc.Years,
+			Hashtags: rc.Hashtags,
+			HasMedia: rc.HasMedia,
+		}
+
+		if rc.Match != "" {
+			match, err := regexp.Compile(rc.Match)
+			if err != nil {
+				return nil, errors.Wrapf(errInvalidRoute, "route %d: %v", i+1, err)
+			}
+
+			route.Match = match
+		}
+
+		routes = append(routes, route)
+	}
+
+	return routes, nil
+}
+
+func validateTags(tags []string) error {
+	for _, tag := range tags {
+		if strings.TrimSpace(tag) == "" {
+			return errEmptyTag
+		}
+	}
+
+	return nil
+}
+
+// validateJournalName checks that a journal name can be used as the name of
+// its JSON file in the archive.
+func validateJournalName(name string) error {
+	if strings.TrimSpace(name) == "" || strings.ContainsAny(name, `/\`) || name == "." || name == ".." {
+		return errors.Wrapf(errInvalidJournal, "%q", name)
+	}
+
+	return nil
+}
+
+// tagRules turns the configured tag rules into converter tag rules.
+func (f *fileConfig) tagRules() ([]converter.TagRule, error) {
+	rules := make([]converter.TagRule, 0, len(f.Tags))
+
+	for i, tc := range f.Tags {
+		rule, err := tc.build()
+		if err != nil {
+			return nil, errors.Wrapf(errInvalidTagRule, "tag rule %d: %v", i+1, err)
+		}
+
+		rules = append(rules, rule)
+	}
+
+	return rules, nil
+}
+
+func (tc *tagRuleConfig) build() (converter.TagRule, error) {
+	rule := converter.TagRule{Tag: strings.TrimSpace(tc.Tag), Keywords: tc.Keywords, AssetTypes: tc.AssetTypes}
+
+	if rule.Tag == "" {
+		return rule, errEmptyTag
+	}
+
+	if err := validateAssetTypes(tc.AssetTypes); err != nil {
+		return rule, err
+	}
+
+	var err error
+
+	if tc.Match != "" {
+		if rule.Match, err = regexp.Compile(tc.Match); err != nil {
+			return rule, errors.Wrap(err, "invalid match")
+		}
+	}
+
+	if rule.Since, err = parseFilterDate(tc.Since); err != nil {
+		return rule, errors.Wrap(err, "invalid since")
+	}
+
+	if rule.Until, err = parseFilterDate(tc.Until); err != nil {
+		return rule, errors.Wrap(err, "invalid until")
+	}
+
+	if !rule.Until.IsZero() {
+		rule.Until = rule.Until.AddDate(0, 0, 1)
+	}
+
+	return rule, nil
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/kpod13/journal2day1/internal/logger"
+)
+
+func writeConfig(t *testing.T, dir, content string) string {
+	t.Helper()
+
+	path := filepath.Join(dir, "config.json")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+	return path
+}
+
+func TestLoadConfigRoutes(t *testing.T) {
+	t.Parallel()
+
+	path := writeConfig(t, t.TempDir(), `{
+  "routes": [
+    {"journal": "Work", "match": "(?i)meeting"},
+    {"journal": "Travel", "hashtags": ["travel", "trip"], "years": [2023, 2024], "hasMedia": true}
+  ]
+}`)
+
+	cfg, err := loadConfig(path)
+	require.NoError(t, err)
+
+	routes, err := cfg.routes()
+	require.NoError(t, err)
+	require.Len(t, routes, 2)
+	require.Equal(t, "Work", routes[0].Journal)
+	require.True(t, routes[0].Match.MatchString("Team MEETING"))
+	require.Equal(t, "Travel", routes[1].Journal)
+	require.Equal(t, []string{"travel", "trip"}, routes[1].Hashtags)
+	require.Equal(t, []int{2023, 2024}, routes[1].Years)
+	require.True(t, routes[1].HasMedia)
+}
+
+func TestLoadConfigErrors(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	_, err := loadConfig(filepath.Join(dir, "missing.json"))
+	require.Error(t, err)
+
+	_, err = loadConfig(writeConfig(t, dir, `{"routes": [{"journal": "Work", "hashtag": "work"}]}`))
+	require.ErrorContains(t, err, "unknown field", "typos are reported")
+
+	testCases := []struct {
+		name   string
+		route  routeConfig
+		target error
+	}{
+		{name: "empty journal", route: routeConfig{HasMedia: true}, target: errInvalidRoute},
+		{name: "path journal", route: routeConfig{Journal: "../Work"}, target: errInvalidRoute},
+		{name: "bad regexp", route: routeConfig{Journal: "Work", Match: "("}, target: errInvalidRoute},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := (&fileConfig{Routes: []routeConfig{tc.route}}).routes()
+
+			require.ErrorIs(t, err, tc.target)
+		})
+	}
+}
+
+func TestLoadConfigTagRules(t *testing.T) {
+	t.Parallel()
+
+	path := writeConfig(t, t.TempDir(), `{
+  "tags": [
+    {"tag": "work", "keywords": ["meeting"], "match": "(?i)standup"},
+    {"tag": "trip", "assetTypes": ["photo", "map"], "since": "2024-05-01", "until": "2024-05-14"}
+  ]
+}`)
+
+	cfg, err := loadConfig(path)
+	require.NoError(t, err)
+
+	rules, err := cfg.tagRules()
+	require.NoError(t, err)
+	require.Len(t, rules, 2)
+	require.Equal(t, "work", rules[0].Tag)
+	require.Equal(t, []string{"meeting"}, rules[0].Keywords)
+	require.True(t, rules[0].Match.MatchString("Standup"))
+	require.Equal(t, []string{"photo", "map"}, rules[1].AssetTypes)
+	require.Equal(t, time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), rules[1].Since)
+	require.Equal(t, time.Date(2024, 5, 15, 0, 0, 0, 0, time.UTC), rules[1].Until, "until is inclusive")
+}
+
+func TestTagRuleErrors(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name   string
+		rule   tagRuleConfig
+		target error
+	}{
+		{name: "empty tag", rule: tagRuleConfig{Tag: " ", Keywords: []string{"x"}}, target: errEmptyTag},
+		{name: "asset type", rule: tagRuleConfig{Tag: "x", AssetTypes: []string{"sticker"}}, target: errInvalidAssetType},
+		{name: "date", rule: tagRuleConfig{Tag: "x", Since: "May 1"}, target: errInvalidDate},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := (&fileConfig{Tags: []tagRuleConfig{tc.rule}}).tagRules()
+
+			require.ErrorIs(t, err, errInvalidTagRule)
+			require.ErrorContains(t, err, tc.target.Error())
+		})
+	}
+}
+
+func TestRunConvertWithConfig(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	inputDir := filepath.Join(tmpDir, "input")
+
+	setupTestData(t, inputDir)
+
+	second := `<div class="pageHeader">16 December 2025</div>
+<div class='title'>Standup</div><div class='bodyText'>Weekly meeting</div>`
+	require.NoError(t, os.WriteFile(filepath.Join(inputDir, "Entries", "2025-12-16_Standup.html"), []byte(second), 0o600))
+
+	configPath := writeConfig(t, tmpDir, `{"routes": [{"journal": "Work", "match": "meeting"}]}`)
+
+	var buf bytes.Buffer
+
+	cmd := newRootCmd(&buf)
+	cmd.SetOut(&buf)
+	cmd.SetArgs([]string{
+		"convert", "-i", inputDir, "-o", filepath.Join(tmpDir, "output.zip"), "-n", "Personal", "--config", configPath,
+	})
+
+	require.NoError(t, cmd.Execute())
+	require.Contains(t, buf.String(), "Personal (1), Work (1)")
+}
+
+func TestRunConvertEmptyTag(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	inputDir := filepath.Join(tmpDir, "input")
+
+	setupTestData(t, inputDir)
+
+	var buf bytes.Buffer
+
+	cfg := &appConfig{
+		inputPath:   inputDir,
+		outputPath:  filepath.Join(tmpDir, "output.zip"),
+		journalName: "Test",
+		timeZone:    "UTC",
+		tags:        []string{"migrated", ""},
+		output:      &buf,
+		log:         logger.New(&buf),
+	}
+
+	require.ErrorIs(t, runConvert(cfg), errEmptyTag)
+	require.NoFileExists(t, cfg.outputPath)
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/kpod13/journal2day1/internal/converter"
+	"github.com/kpod13/journal2day1/internal/parser"
+)
+
+const filterDateFormat = "2006-01-02"
+
+// Sentinel errors for filter flags.
+var (
+	errInvalidDate      = errors.New("dates must be in YYYY-MM-DD format")
+	errInvalidMatch     = errors.New("invalid --match regular expression")
+	errInvalidAssetType = errors.New("unknown asset type")
+)
+
+// filterConfig holds the entry filter flags of the convert command.
+type filterConfig struct {
+	since      string
+	until      string
+	match      string
+	hasMedia   bool
+	assetTypes []string
+}
+
+func (f *filterConfig) isSet() bool {
+	return f.since != "" || f.until != "" || f.match != "" || f.hasMedia || len(f.assetTypes) > 0
+}
+
+// build turns the flags into a converter filter. Both dates are inclusive.
+func (f *filterConfig) build() (converter.Filter, error) {
+	filter := converter.Filter{HasMedia: f.hasMedia}
+
+	var err error
+
+	if filter.Since, err = parseFilterDate(f.since); err != nil {
+		return filter, errors.Wrap(err, "invalid --since")
+	}
+
+	if filter.Until, err = parseFilterDate(f.until); err != nil {
+		return filter, errors.Wrap(err, "invalid --until")
+	}
+
+	if !filter.Until.IsZero() {
+		filter.Until = filter.Until.AddDate(0, 0, 1)
+	}
+
+	if f.match != "" {
+		if filter.Match, err = regexp.Compile(f.match); err != nil {
+			return filter, errors.Wrapf(errInvalidMatch, "%v", err)
+		}
+	}
+
+	if err := validateAssetTypes(f.assetTypes); err != nil {
+		return filter, err
+	}
+
+	filter.AssetTypes = f.assetTypes
+
+	return filter, nil
+}
+
+func validateAssetTypes(types []string) error {
+	known := parser.AssetTypes()
+
+	for _, assetType := range types {
+		if !slices.Contains(known, assetType) {
+			return errors.Wrapf(errInvalidAssetType, "%q (supported: %s)", assetType, strings.Join(known, ", "))
+		}
+	}
+
+	return nil
+}
+
+func parseFilterDate(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	t, err := time.Parse(filterDateFormat, value)
+	if err != nil {
+		return time.Time{}, errors.Wrapf(errInvalidDate, "%q", value)
+	}
+
+	return t, nil
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/kpod13/journal2day1/internal/logger"
+)
+
+func TestFilterConfigBuild(t *testing.T) {
+	t.Parallel()
+
+	cfg := filterConfig{
+		since:      "2024-01-01",
+		until:      "2024-12-31",
+		match:      "(?i)hike",
+		hasMedia:   true,
+		assetTypes: []string{"photo", "map"},
+	}
+
+	require.True(t, cfg.isSet())
+
+	filter, err := cfg.build()
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), filter.Since)
+	require.Equal(t, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), filter.Until, "until is inclusive")
+	require.True(t, filter.Match.MatchString("Morning HIKE"))
+	require.True(t, filter.HasMedia)
+	require.Equal(t, []string{"photo", "map"}, filter.AssetTypes)
+}
+
+func TestFilterConfigBuildErrors(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name   string
+		cfg    filterConfig
+		target error
+	}{
+		{name: "since", cfg: filterConfig{since: "01/02/2024"}, target: errInvalidDate},
+		{name: "until", cfg: filterConfig{until: "2024-13-01"}, target: errInvalidDate},
+		{name: "match", cfg: filterConfig{match: "("}, target: errInvalidMatch},
+		{name: "asset type", cfg: filterConfig{assetTypes: []string{"sticker"}}, target: errInvalidAssetType},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := tc.cfg.build()
+
+			require.ErrorIs(t, err, tc.target)
+		})
+	}
+}
+
+func TestFilterConfigIsSet(t *testing.T) {
+	t.Parallel()
+
+	require.False(t, (&filterConfig{}).isSet())
+	require.True(t, (&filterConfig{hasMedia: true}).isSet())
+}
+
+func TestRunConvertWithFilter(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	inputDir := filepath.Join(tmpDir, "input")
+
+	setupTestData(t, inputDir)
+
+	var buf bytes.Buffer
+
+	cmd := newRootCmd(&buf)
+	cmd.SetOut(&buf)
+	cmd.SetArgs([]string{
+		"convert", "-i", inputDir, "-o", filepath.Join(tmpDir, "output.zip"),
+		"--since", "2025-12-16", "--match", "Test",
+	})
+
+	require.NoError(t, cmd.Execute())
+	require.Contains(t, buf.String(), "Excluded entries")
+	require.Contains(t, buf.String(), "1 (1 by --since)")
+}
+
+func TestRunConvertInvalidFilter(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	inputDir := filepath.Join(tmpDir, "input")
+
+	setupTestData(t, inputDir)
+
+	var buf bytes.Buffer
+
+	cfg := &appConfig{
+		inputPath:   inputDir,
+		outputPath:  filepath.Join(tmpDir, "output.zip"),
+		journalName: "Test",
+		timeZone:    "UTC",
+		filter:      filterConfig{since: "yesterday"},
+		output:      &buf,
+		log:         logger.New(&buf),
+	}
+
+	require.ErrorIs(t, runConvert(cfg), errInvalidDate)
+	require.NoFileExists(t, cfg.outputPath)
+}
@@ -6,6 +6,7 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"runtime"
 
 	"github.com/pkg/errors"
 	"github.com/schollz/progressbar/v3"
@@ -13,6 +14,7 @@ import (
 
 	"github.com/kpod13/journal2day1/internal/converter"
 	"github.com/kpod13/journal2day1/internal/logger"
+	"github.com/kpod13/journal2day1/internal/parser"
 )
 
 // Build-time variables.
@@ -26,21 +28,57 @@ var (
 var (
 	errMissingEntries   = errors.New("input directory does not contain Entries subdirectory")
 	errMissingResources = errors.New("input directory does not contain Resources subdirectory")
+	errUnsupportedInput = errors.New("input must be an export directory or a .zip, .tar.gz or .tgz archive")
+	errInvalidJobs      = errors.New("jobs must not be negative")
+	errEntriesSkipped   = errors.New("some entries could not be converted")
+	errReportNoOutput   = errors.New("--report needs --output to place the report next to it")
+)
+
+// Exit codes.
+const (
+	exitFailure = 1
+	exitPartial = 2 // the archive was written, but entries were skipped
 )
 
 func main() {
 	if err := newRootCmd(os.Stdout).Execute(); err != nil {
 		_, _ = fmt.Fprintln(os.Stderr, err) //nolint:errcheck // stderr write errors are not critical
 
-		os.Exit(1)
+		os.Exit(exitCode(err))
 	}
 }
 
+func exitCode(err error) int {
+	if errors.Is(err, errEntriesSkipped) {
+		return exitPartial
+	}
+
+	return exitFailure
+}
+
 type appConfig struct {
 	inputPath   string
 	outputPath  string
 	journalName string
 	timeZone    string
+	locale      string
+	mediaAtEnd  bool
+	jobs        int
+	strict      bool
+	writeReport bool
+	dryRun      bool
+	randomUUIDs bool
+	statePath   string
+	filter      filterConfig
+	splitSize   string
+	splitBy     string
+	configPath  string
+	tags        []string
+	stripTags   bool
+	entryLoc    string
+	actTags     bool
+	moodTags    []string
+	livePhotos  string
 	output      io.Writer
 	log         *logger.Logger
 }
@@ -57,7 +95,8 @@ func newRootCmd(output io.Writer) *cobra.Command {
 		Long: logger.Bold("journal2day1") + " converts Apple Journal HTML exports to DayOne JSON ZIP format.\n\n" +
 			"The tool reads the Apple Journal export directory (containing " +
 			logger.Cyan("Entries/") + " and " + logger.Cyan("Resources/") +
-			"\nsubdirectories) and creates a ZIP archive that can be imported into DayOne.\n\n" +
+			"\nsubdirectories), or a ZIP or tar.gz archive of it, and creates a ZIP archive that can be\n" +
+			"imported into DayOne.\n\n" +
 			logger.Dim("Example:") + "\n" +
 			"  " + logger.Green("journal2day1 convert -i ~/AppleJournalEntries -o ~/dayone-import.zip"),
 	}
@@ -106,23 +145,63 @@ func newConvertCmd(cfg *appConfig) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "convert",
 		Short: "Convert Apple Journal export to DayOne format",
-		RunE: func(_ *cobra.Command, _ []string) error {
-			return runConvert(cfg)
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			err := runConvert(cfg)
+			if errors.Is(err, errEntriesSkipped) {
+				// The summary has been printed, usage would only bury it.
+				cmd.SilenceUsage = true
+			}
+
+			return err
 		},
 	}
 
-	cmd.Flags().StringVarP(&cfg.inputPath, "input", "i", "", "Path to Apple Journal export directory (required)")
-	cmd.Flags().StringVarP(&cfg.outputPath, "output", "o", "", "Path to output ZIP file (required)")
+	cmd.Flags().StringVarP(&cfg.inputPath, "input", "i", "", "Path to Apple Journal export directory or archive (required)")
+	cmd.Flags().StringVarP(&cfg.outputPath, "output", "o", "", "Path to output ZIP file (required unless --dry-run)")
 	cmd.Flags().StringVarP(&cfg.journalName, "name", "n", "Journal", "Name of the journal in DayOne")
 	cmd.Flags().StringVarP(&cfg.timeZone, "timezone", "t", "Europe/Sofia", "Timezone for entries")
+	cmd.Flags().StringVarP(&cfg.locale, "locale", "l", "",
+		"Locale of entry dates, e.g. de or en-US (detected automatically when empty)")
+	cmd.Flags().BoolVar(&cfg.mediaAtEnd, "media-at-end", false, "Place photos and videos after the entry text")
+	cmd.Flags().IntVarP(&cfg.jobs, "jobs", "j", runtime.NumCPU(),
+		"Number of entries parsed and media files copied concurrently (0 uses one per CPU)")
+	cmd.Flags().BoolVar(&cfg.strict, "strict", false, "Abort on the first entry that fails to parse")
+	cmd.Flags().BoolVar(&cfg.writeReport, "report", false,
+		"Write a JSON report of every entry and asset next to the output ZIP")
+	cmd.Flags().BoolVar(&cfg.dryRun, "dry-run", false,
+		"Show what would be converted without copying media or writing the ZIP")
+	cmd.Flags().BoolVar(&cfg.randomUUIDs, "random-uuids", false,
+		"Give entries random UUIDs instead of UUIDs derived from the export")
+	cmd.Flags().StringVar(&cfg.statePath, "state", "",
+		"State file of earlier runs; only new or changed entries are converted")
+	cmd.Flags().StringVar(&cfg.filter.since, "since", "", "Convert only entries on or after this date (YYYY-MM-DD)")
+	cmd.Flags().StringVar(&cfg.filter.until, "until", "", "Convert only entries on or before this date (YYYY-MM-DD)")
+	cmd.Flags().StringVar(&cfg.filter.match, "match", "",
+		"Convert only entries whose title or text matches this regular expression")
+	cmd.Flags().BoolVar(&cfg.filter.hasMedia, "has-media", false, "Convert only entries with photos, videos or audio recordings")
+	cmd.Flags().StringSliceVar(&cfg.filter.assetTypes, "asset-type", nil,
+		"Convert only entries with an asset of this type, e.g. photo or map (repeatable)")
+	cmd.Flags().StringVar(&cfg.splitSize, "split-size", "",
+		"Split the output into archives of at most this size, e.g. 2GB or 500MB")
+	cmd.Flags().StringVar(&cfg.splitBy, "split-by", "", "Write one archive per year or month of entries (year|month)")
+	cmd.Flags().StringVar(&cfg.configPath, "config", "",
+		"JSON config file with rules that route entries to journals and tag them")
+	cmd.Flags().StringSliceVar(&cfg.tags, "tag", nil, "Add this tag to every entry (repeatable)")
+	cmd.Flags().BoolVar(&cfg.stripTags, "strip-hashtags", false, "Remove #hashtags from the text after turning them into tags")
+	cmd.Flags().StringVar(&cfg.entryLoc, "entry-location", converter.EntryLocationFirst,
+		"Take the entry location from the first or last asset with one, or none (first|last|none)")
+	cmd.Flags().BoolVar(&cfg.actTags, "activity-tags", false,
+		"Tag entries with the type of their motion activities, e.g. activity:walk")
+	cmd.Flags().StringSliceVar(&cfg.moodTags, "mood-tags", nil,
+		"Tag entries with these parts of their State of Mind, e.g. mood:pleasant (valence|labels|associations)")
+	cmd.Flags().StringVar(&cfg.livePhotos, "live-photos", converter.LivePhotoStill,
+		"Attach the still of Live Photos, both the still and its motion video, or the video (still|both|video)")
 
 	if err := cmd.MarkFlagRequired("input"); err != nil {
 		panic(fmt.Sprintf("failed to mark input flag required: %v", err))
 	}
 
-	if err := cmd.MarkFlagRequired("output"); err != nil {
-		panic(fmt.Sprintf("failed to mark output flag required: %v", err))
-	}
+	cmd.MarkFlagsOneRequired("output", "dry-run")
 
 	return cmd
 }
@@ -154,22 +233,172 @@ func runConvert(cfg *appConfig) error {
 		return err
 	}
 
-	absOutput, err := filepath.Abs(cfg.outputPath)
+	absOutput, err := resolveOutputPath(cfg)
+	if err != nil {
+		return err
+	}
+
+	conv, err := newConverter(cfg, absInput)
 	if err != nil {
-		return errors.Wrap(err, "failed to resolve output path")
+		return err
 	}
 
 	printConvertInfo(cfg.log, absInput, absOutput, cfg.journalName, cfg.timeZone)
 
+	err = conv.Convert(absOutput)
+
+	nothingNew := errors.Is(err, converter.ErrNothingNew)
+	if err != nil && !nothingNew {
+		return errors.Wrap(err, "failed to convert")
+	}
+
+	switch {
+	case nothingNew:
+		// No entries were converted, so there is nothing to sum up.
+	case cfg.dryRun:
+		printDryRunSummary(cfg.log, conv.Report())
+	default:
+		printSummary(cfg.log, conv.Report())
+	}
+
+	if cfg.writeReport {
+		reportPath := reportPathFor(absOutput)
+		if err := conv.Report().WriteJSON(reportPath); err != nil {
+			return err
+		}
+
+		cfg.log.KeyValue("Report", reportPath)
+	}
+
+	if failures := conv.Failures(); len(failures) > 0 {
+		printFailures(cfg.log, failures)
+
+		return errors.Wrapf(errEntriesSkipped, "%d skipped", len(failures))
+	}
+
+	switch {
+	case nothingNew:
+		cfg.log.Success("No new or changed entries since the last run, nothing was written.")
+	case cfg.dryRun:
+		cfg.log.Success("Dry run completed, nothing was written.")
+	default:
+		cfg.log.Success("Conversion completed successfully!")
+	}
+
+	return nil
+}
+
+// resolveOutputPath returns the absolute output path. It may be empty in a
+// dry run, unless a report has to be written next to it.
+func resolveOutputPath(cfg *appConfig) (string, error) {
+	if cfg.outputPath == "" {
+		if cfg.dryRun && cfg.writeReport {
+			return "", errReportNoOutput
+		}
+
+		return "", nil
+	}
+
+	absOutput, err := filepath.Abs(cfg.outputPath)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to resolve output path")
+	}
+
+	return absOutput, nil
+}
+
+func newConverter(cfg *appConfig, absInput string) (*converter.Converter, error) {
+	jobs := cfg.jobs
+	if jobs < 0 {
+		return nil, errors.Wrapf(errInvalidJobs, "got %d", jobs)
+	}
+
+	if jobs == 0 {
+		jobs = runtime.NumCPU()
+	}
+
 	conv := converter.NewConverter(absInput, cfg.journalName)
 	conv.SetTimeZone(cfg.timeZone)
+	conv.SetMediaAtEnd(cfg.mediaAtEnd)
+	conv.SetJobs(jobs)
+	conv.SetLenient(!cfg.strict)
+	conv.SetDryRun(cfg.dryRun)
+	conv.SetRandomUUIDs(cfg.randomUUIDs)
+	conv.SetStatePath(cfg.statePath)
+	conv.SetProgressFunc(newProgressFunc(cfg.output))
+
+	if cfg.locale != "" {
+		if err := conv.SetLocale(cfg.locale); err != nil {
+			return nil, errors.Wrap(err, "invalid locale")
+		}
+	}
+
+	if cfg.filter.isSet() {
+		filter, err := cfg.filter.build()
+		if err != nil {
+			return nil, err
+		}
+
+		conv.SetFilter(filter)
+	}
+
+	if err := applyConfigFile(conv, cfg.configPath); err != nil {
+		return nil, err
+	}
+
+	if err := applyEntryOptions(conv, cfg); err != nil {
+		return nil, err
+	}
+
+	maxSize, err := parseSize(cfg.splitSize)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid --split-size")
+	}
+
+	if err := conv.SetSplit(converter.Split{By: cfg.splitBy, MaxSize: maxSize}); err != nil {
+		return nil, errors.Wrap(err, "invalid --split-by")
+	}
+
+	return conv, nil
+}
+
+// applyEntryOptions applies the options shaping the tags, location and
+// attachments of converted entries.
+func applyEntryOptions(conv *converter.Converter, cfg *appConfig) error {
+	if err := validateTags(cfg.tags); err != nil {
+		return errors.Wrap(err, "invalid --tag")
+	}
+
+	conv.SetTags(cfg.tags)
+	conv.SetStripHashtags(cfg.stripTags)
+	conv.SetActivityTags(cfg.actTags)
 
+	if err := conv.SetMoodTags(cfg.moodTags); err != nil {
+		return errors.Wrap(err, "invalid --mood-tags")
+	}
+
+	if cfg.entryLoc != "" {
+		if err := conv.SetEntryLocation(cfg.entryLoc); err != nil {
+			return errors.Wrap(err, "invalid --entry-location")
+		}
+	}
+
+	if cfg.livePhotos != "" {
+		if err := conv.SetLivePhotos(cfg.livePhotos); err != nil {
+			return errors.Wrap(err, "invalid --live-photos")
+		}
+	}
+
+	return nil
+}
+
+func newProgressFunc(output io.Writer) converter.ProgressFunc {
 	var bar *progressbar.ProgressBar
 
-	conv.SetProgressFunc(func(current, total int) {
+	return func(current, total int) {
 		if bar == nil {
 			bar = progressbar.NewOptions(total,
-				progressbar.OptionSetWriter(cfg.output),
+				progressbar.OptionSetWriter(output),
 				progressbar.OptionEnableColorCodes(true),
 				progressbar.OptionShowCount(),
 				progressbar.OptionSetWidth(getProgressBarWidth()),
@@ -181,24 +410,32 @@ func runConvert(cfg *appConfig) error {
 					BarEnd:        "]",
 				}),
 				progressbar.OptionOnCompletion(func() {
-					_, _ = fmt.Fprintln(cfg.output) //nolint:errcheck // progress bar completion write is not critical
+					_, _ = fmt.Fprintln(output) //nolint:errcheck // progress bar completion write is not critical
 				}),
 			)
 		}
 
 		_ = bar.Set(current) //nolint:errcheck // progress bar errors are not critical
-	})
-
-	if err := conv.Convert(absOutput); err != nil {
-		return errors.Wrap(err, "failed to convert")
 	}
+}
 
-	cfg.log.Success("Conversion completed successfully!")
+func printFailures(log *logger.Logger, failures []parser.EntryError) {
+	log.Warn("Skipped %d entries that could not be parsed:", len(failures))
 
-	return nil
+	for _, failure := range failures {
+		log.Println("  %s: %v", failure.Path, failure.Err)
+	}
 }
 
 func validateInputDir(absInput string) error {
+	if info, err := os.Stat(absInput); err == nil && !info.IsDir() {
+		if parser.IsArchive(absInput) {
+			return nil
+		}
+
+		return errors.Wrapf(errUnsupportedInput, "%s", absInput)
+	}
+
 	entriesDir := filepath.Join(absInput, "Entries")
 	if _, err := os.Stat(entriesDir); os.IsNotExist(err) {
 		return errors.Wrapf(errMissingEntries, "%s", absInput)
@@ -213,6 +450,10 @@ func validateInputDir(absInput string) error {
 }
 
 func printConvertInfo(log *logger.Logger, input, output, journalName, timeZone string) {
+	if output == "" {
+		output = "(none, dry run)"
+	}
+
 	log.Header("Journal Conversion")
 	log.KeyValue("Input", input)
 	log.KeyValue("Output", output)
@@ -4,12 +4,16 @@ import (
 	"bytes"
 	"os"
 	"path/filepath"
+	"runtime"
+	"strconv"
 	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 
+	"github.com/kpod13/journal2day1/internal/converter"
 	"github.com/kpod13/journal2day1/internal/logger"
+	"github.com/kpod13/journal2day1/internal/parser"
 )
 
 func TestNewRootCmd(t *testing.T) {
@@ -96,6 +100,30 @@ func TestValidateInputDir(t *testing.T) {
 		require.Error(t, err)
 		require.ErrorIs(t, err, errMissingResources)
 	})
+
+	t.Run("archive file", func(t *testing.T) {
+		t.Parallel()
+
+		archivePath := filepath.Join(t.TempDir(), "export.zip")
+
+		require.NoError(t, os.WriteFile(archivePath, []byte("zip"), 0o600))
+
+		err := validateInputDir(archivePath)
+
+		require.NoError(t, err)
+	})
+
+	t.Run("unsupported file", func(t *testing.T) {
+		t.Parallel()
+
+		filePath := filepath.Join(t.TempDir(), "export.txt")
+
+		require.NoError(t, os.WriteFile(filePath, []byte("text"), 0o600))
+
+		err := validateInputDir(filePath)
+
+		require.ErrorIs(t, err, errUnsupportedInput)
+	})
 }
 
 func TestPrintConvertInfo(t *testing.T) {
@@ -147,6 +175,17 @@ func TestNewConvertCmd(t *testing.T) {
 
 	require.NotNil(t, tzFlag)
 	require.Equal(t, "Europe/Sofia", tzFlag.DefValue)
+
+	mediaAtEndFlag := cmd.Flags().Lookup("media-at-end")
+
+	require.NotNil(t, mediaAtEndFlag)
+	require.Equal(t, "false", mediaAtEndFlag.DefValue)
+
+	jobsFlag := cmd.Flags().Lookup("jobs")
+
+	require.NotNil(t, jobsFlag)
+	require.Equal(t, "j", jobsFlag.Shorthand)
+	require.Equal(t, strconv.Itoa(runtime.NumCPU()), jobsFlag.DefValue)
 }
 
 func TestRunConvert(t *testing.T) {
@@ -365,3 +404,262 @@ func TestConvertCommandInvalidInputDir(t *testing.T) {
 
 	require.Error(t, err)
 }
+
+func TestRunConvertInvalidLocale(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	inputDir := filepath.Join(tmpDir, "input")
+
+	setupTestData(t, inputDir)
+
+	var buf bytes.Buffer
+
+	cfg := &appConfig{
+		inputPath:   inputDir,
+		outputPath:  filepath.Join(tmpDir, "output.zip"),
+		journalName: "Test",
+		timeZone:    "UTC",
+		locale:      "xx",
+		output:      &buf,
+		log:         logger.New(&buf),
+	}
+
+	err := runConvert(cfg)
+
+	require.ErrorIs(t, err, parser.ErrUnknownLocale)
+	require.NoFileExists(t, cfg.outputPath)
+}
+
+func TestRunConvertNegativeJobs(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	inputDir := filepath.Join(tmpDir, "input")
+
+	setupTestData(t, inputDir)
+
+	var buf bytes.Buffer
+
+	cfg := &appConfig{
+		inputPath:   inputDir,
+		outputPath:  filepath.Join(tmpDir, "output.zip"),
+		journalName: "Test",
+		timeZone:    "UTC",
+		jobs:        -1,
+		output:      &buf,
+		log:         logger.New(&buf),
+	}
+
+	err := runConvert(cfg)
+
+	require.ErrorIs(t, err, errInvalidJobs)
+	require.NoFileExists(t, cfg.outputPath)
+}
+
+func TestRunConvertSkippedEntries(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name   string
+		strict bool
+	}{
+		{name: "lenient", strict: false},
+		{name: "strict", strict: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			tmpDir := t.TempDir()
+			inputDir := filepath.Join(tmpDir, "input")
+
+			setupTestData(t, inputDir)
+
+			brokenPath := filepath.Join(inputDir, "Entries", "2025-12-16_Broken.html")
+			require.NoError(t, os.Symlink(filepath.Join(tmpDir, "missing.html"), brokenPath))
+
+			var buf bytes.Buffer
+
+			cfg := &appConfig{
+				inputPath:   inputDir,
+				outputPath:  filepath.Join(tmpDir, "output.zip"),
+				journalName: "Test",
+				timeZone:    "UTC",
+				strict:      tc.strict,
+				output:      &buf,
+				log:         logger.New(&buf),
+			}
+
+			err := runConvert(cfg)
+			require.Error(t, err)
+
+			if tc.strict {
+				require.NotErrorIs(t, err, errEntriesSkipped)
+				require.Equal(t, exitFailure, exitCode(err))
+				require.NoFileExists(t, cfg.outputPath)
+
+				return
+			}
+
+			require.ErrorIs(t, err, errEntriesSkipped)
+			require.Equal(t, exitPartial, exitCode(err))
+			require.FileExists(t, cfg.outputPath)
+			require.Contains(t, buf.String(), "Skipped 1 entries")
+			require.Contains(t, buf.String(), brokenPath)
+		})
+	}
+}
+
+func TestRunConvertWithState(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	inputDir := filepath.Join(tmpDir, "input")
+
+	setupTestData(t, inputDir)
+
+	run := func(output string) string {
+		var buf bytes.Buffer
+
+		cfg := &appConfig{
+			inputPath:   inputDir,
+			outputPath:  filepath.Join(tmpDir, output),
+			journalName: "Test",
+			timeZone:    "UTC",
+			statePath:   filepath.Join(tmpDir, "state.json"),
+			output:      &buf,
+			log:         logger.New(&buf),
+		}
+
+		require.NoError(t, runConvert(cfg))
+
+		return buf.String()
+	}
+
+	require.Contains(t, run("first.zip"), "Conversion completed successfully!")
+	require.FileExists(t, filepath.Join(tmpDir, "state.json"))
+
+	require.Contains(t, run("second.zip"), "No new or changed entries")
+	require.NoFileExists(t, filepath.Join(tmpDir, "second.zip"))
+}
+
+func TestRunConvertNothingNewReportsFailures(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	inputDir := filepath.Join(tmpDir, "input")
+
+	setupTestData(t, inputDir)
+
+	run := func(output string) (string, error) {
+		var buf bytes.Buffer
+
+		cfg := &appConfig{
+			inputPath:   inputDir,
+			outputPath:  filepath.Join(tmpDir, output),
+			journalName: "Test",
+			timeZone:    "UTC",
+			statePath:   filepath.Join(tmpDir, "state.json"),
+			writeReport: true,
+			output:      &buf,
+			log:         logger.New(&buf),
+		}
+
+		err := runConvert(cfg)
+
+		return buf.String(), err
+	}
+
+	_, err := run("first.zip")
+	require.NoError(t, err)
+
+	brokenPath := filepath.Join(inputDir, "Entries", "2025-12-16_Broken.html")
+	require.NoError(t, os.Symlink(filepath.Join(tmpDir, "missing.html"), brokenPath))
+
+	out, err := run("second.zip")
+	require.ErrorIs(t, err, errEntriesSkipped)
+	require.Equal(t, exitPartial, exitCode(err))
+	require.Contains(t, out, brokenPath)
+	require.NoFileExists(t, filepath.Join(tmpDir, "second.zip"))
+	require.FileExists(t, filepath.Join(tmpDir, "second-report.json"))
+}
+
+func TestRunConvertInvalidEntryLocation(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	inputDir := filepath.Join(tmpDir, "input")
+
+	setupTestData(t, inputDir)
+
+	var buf bytes.Buffer
+
+	cfg := &appConfig{
+		inputPath:   inputDir,
+		outputPath:  filepath.Join(tmpDir, "output.zip"),
+		journalName: "Test",
+		timeZone:    "UTC",
+		entryLoc:    "middle",
+		output:      &buf,
+		log:         logger.New(&buf),
+	}
+
+	err := runConvert(cfg)
+
+	require.ErrorIs(t, err, converter.ErrInvalidLocationRule)
+	require.NoFileExists(t, cfg.outputPath)
+}
+
+func TestRunConvertInvalidMoodTags(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	inputDir := filepath.Join(tmpDir, "input")
+
+	setupTestData(t, inputDir)
+
+	var buf bytes.Buffer
+
+	cfg := &appConfig{
+		inputPath:   inputDir,
+		outputPath:  filepath.Join(tmpDir, "output.zip"),
+		journalName: "Test",
+		timeZone:    "UTC",
+		moodTags:    []string{"colour"},
+		output:      &buf,
+		log:         logger.New(&buf),
+	}
+
+	err := runConvert(cfg)
+
+	require.ErrorIs(t, err, converter.ErrInvalidMoodTag)
+	require.NoFileExists(t, cfg.outputPath)
+}
+
+func TestRunConvertInvalidLivePhotos(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	inputDir := filepath.Join(tmpDir, "input")
+
+	setupTestData(t, inputDir)
+
+	var buf bytes.Buffer
+
+	cfg := &appConfig{
+		inputPath:   inputDir,
+		outputPath:  filepath.Join(tmpDir, "output.zip"),
+		journalName: "Test",
+		timeZone:    "UTC",
+		livePhotos:  "motion",
+		output:      &buf,
+		log:         logger.New(&buf),
+	}
+
+	err := runConvert(cfg)
+
+	require.ErrorIs(t, err, converter.ErrInvalidLivePhotoPolicy)
+	require.NoFileExists(t, cfg.outputPath)
+}
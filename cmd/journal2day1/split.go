@@ -0,0 +1,61 @@
+package main
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+var errInvalidSize = errors.New("sizes must be a number with an optional unit, e.g. 500MB or 2GB")
+
+// sizeUnits maps size suffixes to bytes. Longer suffixes come first so "MB"
+// is not read as "B".
+var sizeUnits = []struct {
+	suffix string
+	bytes  float64
+}{
+	{suffix: "KIB", bytes: 1 << 10},
+	{suffix: "MIB", bytes: 1 << 20},
+	{suffix: "GIB", bytes: 1 << 30},
+	{suffix: "KB", bytes: 1e3},
+	{suffix: "MB", bytes: 1e6},
+	{suffix: "GB", bytes: 1e9},
+	{suffix: "K", bytes: 1e3},
+	{suffix: "M", bytes: 1e6},
+	{suffix: "G", bytes: 1e9},
+	{suffix: "B", bytes: 1},
+}
+
+// parseSize parses a size like "2GB", "1.5G", "500MiB" or "1048576". KB, MB
+// and GB are decimal units, KiB, MiB and GiB binary ones. An empty string is
+// zero.
+func parseSize(value string) (int64, error) {
+	text := strings.ToUpper(strings.TrimSpace(value))
+	if text == "" {
+		return 0, nil
+	}
+
+	multiplier := 1.0
+
+	for _, unit := range sizeUnits {
+		if number, ok := strings.CutSuffix(text, unit.suffix); ok {
+			text, multiplier = strings.TrimSpace(number), unit.bytes
+
+			break
+		}
+	}
+
+	number, err := strconv.ParseFloat(text, 64)
+	if err != nil || math.IsNaN(number) || math.IsInf(number, 0) || number <= 0 {
+		return 0, errors.Wrapf(errInvalidSize, "%q", value)
+	}
+
+	size := number * multiplier
+	if size >= math.MaxInt64 {
+		return 0, errors.Wrapf(errInvalidSize, "%q is too large", value)
+	}
+
+	return int64(size), nil
+}
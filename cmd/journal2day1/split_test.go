@@ -0,0 +1,37 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSize(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		value string
+		want  int64
+	}{
+		{value: "", want: 0},
+		{value: "1048576", want: 1048576},
+		{value: "500MB", want: 500_000_000},
+		{value: "2GB", want: 2_000_000_000},
+		{value: "1.5g", want: 1_500_000_000},
+		{value: "64 KiB", want: 64 << 10},
+		{value: "2GiB", want: 2 << 30},
+	}
+
+	for _, tc := range testCases {
+		size, err := parseSize(tc.value)
+
+		require.NoError(t, err, tc.value)
+		require.Equal(t, tc.want, size, tc.value)
+	}
+
+	for _, value := range []string{"GB", "-1MB", "two", "0", "NaN", "Inf", "+Inf", "infGB", "1e30GB"} {
+		_, err := parseSize(value)
+
+		require.ErrorIs(t, err, errInvalidSize, value)
+	}
+}
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"fmt"
+	"maps"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/kpod13/journal2day1/internal/converter"
+	"github.com/kpod13/journal2day1/internal/logger"
+)
+
+const bytesPerKiB = 1024
+
+// reportPathFor returns the path of the JSON report written next to the
+// output ZIP, e.g. "import-report.json" for "import.zip".
+func reportPathFor(outputPath string) string {
+	return strings.TrimSuffix(outputPath, filepath.Ext(outputPath)) + "-report.json"
+}
+
+func printSummary(log *logger.Logger, report *converter.Report) {
+	totals := report.Totals
+
+	log.Header("Summary")
+	log.KeyValue("Entries", fmt.Sprintf("%d converted, %d skipped", totals.Entries, totals.FailedEntries))
+	printLeftOutEntries(log, report)
+	log.KeyValue("Media", fmt.Sprintf("%d copied (%s), %d skipped, %d failed",
+		totals.CopiedAssets, formatBytes(totals.CopiedBytes), totals.SkippedAssets, totals.FailedAssets))
+	printConvertedAssets(log, report)
+
+	if totals.OrphanedResources > 0 {
+		log.KeyValue("Unreferenced resources", fmt.Sprintf("%d", totals.OrphanedResources))
+	}
+
+	printJournals(log, report)
+	printArchives(log, report)
+
+	for _, failed := range report.Assets(converter.AssetFailed) {
+		log.Warn("%s: %s %s: %s", failed.Entry, failed.Asset.Type, failed.Asset.ID, failed.Asset.Reason)
+	}
+}
+
+// printDryRunSummary shows what a conversion would produce.
+func printDryRunSummary(log *logger.Logger, report *converter.Report) {
+	totals := report.Totals
+
+	log.Header("Dry Run")
+	log.KeyValue("Entries", fmt.Sprintf("%d (%d could not be parsed)", totals.Entries, totals.FailedEntries))
+	printLeftOutEntries(log, report)
+
+	if totals.FirstDate != "" {
+		log.KeyValue("Date range", formatDate(totals.FirstDate)+" – "+formatDate(totals.LastDate))
+	}
+
+	log.KeyValue("Media", fmt.Sprintf("%d photos, %d videos, %d audio recordings (%s)",
+		totals.Photos, totals.Videos, totals.Audios, formatBytes(totals.CopiedBytes)))
+	printConvertedAssets(log, report)
+
+	printJournals(log, report)
+	printArchives(log, report)
+
+	problems := []struct {
+		status converter.AssetStatus
+		label  string
+	}{
+		{status: converter.AssetSkipped, label: "would be skipped"},
+		{status: converter.AssetFailed, label: "cannot be copied"},
+	}
+
+	for _, problem := range problems {
+		assets := report.Assets(problem.status)
+		if len(assets) == 0 {
+			continue
+		}
+
+		log.Warn("%d assets %s:", len(assets), problem.label)
+
+		for _, a := range assets {
+			log.Println("  %s: %s %s: %s", a.Entry, a.Asset.Type, a.Asset.ID, a.Asset.Reason)
+		}
+	}
+
+	if totals.UndatedEntries > 0 {
+		log.Warn("%d entries have no detectable date:", totals.UndatedEntries)
+
+		for i := range report.Entries {
+			if report.Entries[i].Undated {
+				log.Println("  %s", report.Entries[i].Path)
+			}
+		}
+	}
+}
+
+// printConvertedAssets shows how many assets, such as maps, went into the
+// entries themselves instead of being attached.
+func printConvertedAssets(log *logger.Logger, report *converter.Report) {
+	if report.Totals.ConvertedAssets > 0 {
+		log.KeyValue("Converted assets", fmt.Sprintf("%d", report.Totals.ConvertedAssets))
+	}
+}
+
+// printJournals shows how many entries went to each journal when entries
+// were routed to more than one.
+func printJournals(log *logger.Logger, report *converter.Report) {
+	counts := make(map[string]int)
+
+	for i := range report.Entries {
+		counts[report.Entries[i].Journal]++
+	}
+
+	if len(counts) < 2 {
+		return
+	}
+
+	journals := make([]string, 0, len(counts))
+
+	for _, name := range slices.Sorted(maps.Keys(counts)) {
+		journals = append(journals, fmt.Sprintf("%s (%d)", name, counts[name]))
+	}
+
+	log.KeyValue("Journals", strings.Join(journals, ", "))
+}
+
+// printArchives lists the archives of a split output.
+func printArchives(log *logger.Logger, report *converter.Report) {
+	if len(report.Archives) < 2 {
+		return
+	}
+
+	log.KeyValue("Archives", strings.Join(report.Archives, ", "))
+}
+
+// printLeftOutEntries shows the entries left out by the state file and by
+// each filter rule.
+func printLeftOutEntries(log *logger.Logger, report *converter.Report) {
+	if report.Totals.UnchangedEntries > 0 {
+		log.KeyValue("Unchanged entries", fmt.Sprintf("%d", report.Totals.UnchangedEntries))
+	}
+
+	if report.Totals.ExcludedEntries == 0 {
+		return
+	}
+
+	rules := make([]string, 0, len(report.ExcludedEntries))
+
+	for _, rule := range []string{
+		converter.RuleSince, converter.RuleUntil, converter.RuleMatch,
+		converter.RuleHasMedia, converter.RuleAssetType,
+	} {
+		if count := report.ExcludedEntries[rule]; count > 0 {
+			rules = append(rules, fmt.Sprintf("%d by --%s", count, rule))
+		}
+	}
+
+	log.KeyValue("Excluded entries", fmt.Sprintf("%d (%s)", report.Totals.ExcludedEntries, strings.Join(rules, ", ")))
+}
+
+// formatDate shortens an ISO 8601 timestamp to its date.
+func formatDate(timestamp string) string {
+	date, _, _ := strings.Cut(timestamp, "T")
+
+	return date
+}
+
+// formatBytes formats a size with a binary unit, e.g. "1.5 MiB".
+func formatBytes(size int64) string {
+	if size < bytesPerKiB {
+		return fmt.Sprintf("%d B", size)
+	}
+
+	value := float64(size) / bytesPerKiB
+	units := []string{"KiB", "MiB", "GiB", "TiB"}
+	unit := 0
+
+	for value >= bytesPerKiB && unit < len(units)-1 {
+		value /= bytesPerKiB
+		unit++
+	}
+
+	return fmt.Sprintf("%.1f %s", value, units[unit])
+}
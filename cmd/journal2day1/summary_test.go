@@ -0,0 +1,182 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/kpod13/journal2day1/internal/converter"
+	"github.com/kpod13/journal2day1/internal/logger"
+)
+
+func TestFormatBytes(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		size     int64
+		expected string
+	}{
+		{size: 0, expected: "0 B"},
+		{size: 1023, expected: "1023 B"},
+		{size: 1536, expected: "1.5 KiB"},
+		{size: 5 * 1024 * 1024, expected: "5.0 MiB"},
+		{size: 3 << 40, expected: "3.0 TiB"},
+		{size: 2048 << 40, expected: "2048.0 TiB"},
+	}
+
+	for _, tc := range testCases {
+		require.Equal(t, tc.expected, formatBytes(tc.size))
+	}
+}
+
+func TestReportPathFor(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, "/out/import-report.json", reportPathFor("/out/import.zip"))
+	require.Equal(t, "/out/import-report.json", reportPathFor("/out/import"))
+}
+
+func TestRunConvertWritesReport(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	inputDir := filepath.Join(tmpDir, "input")
+
+	setupTestData(t, inputDir)
+
+	var buf bytes.Buffer
+
+	cfg := &appConfig{
+		inputPath:   inputDir,
+		outputPath:  filepath.Join(tmpDir, "output.zip"),
+		journalName: "Test",
+		timeZone:    "UTC",
+		writeReport: true,
+		output:      &buf,
+		log:         logger.New(&buf),
+	}
+
+	require.NoError(t, runConvert(cfg))
+
+	reportPath := filepath.Join(tmpDir, "output-report.json")
+	data, err := os.ReadFile(reportPath)
+	require.NoError(t, err)
+
+	var report converter.Report
+
+	require.NoError(t, json.Unmarshal(data, &report))
+	require.Equal(t, 1, report.Totals.Entries)
+
+	output := buf.String()
+	require.Contains(t, output, "Summary")
+	require.Contains(t, output, "1 converted, 0 skipped")
+	require.Contains(t, output, reportPath)
+}
+
+func TestRunConvertWithoutReport(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	inputDir := filepath.Join(tmpDir, "input")
+
+	setupTestData(t, inputDir)
+
+	var buf bytes.Buffer
+
+	cfg := &appConfig{
+		inputPath:   inputDir,
+		outputPath:  filepath.Join(tmpDir, "output.zip"),
+		journalName: "Test",
+		timeZone:    "UTC",
+		output:      &buf,
+		log:         logger.New(&buf),
+	}
+
+	require.NoError(t, runConvert(cfg))
+	require.NoFileExists(t, filepath.Join(tmpDir, "output-report.json"))
+}
+
+func TestRunConvertDryRun(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	inputDir := filepath.Join(tmpDir, "input")
+
+	setupTestData(t, inputDir)
+
+	undatedPath := filepath.Join(inputDir, "Entries", "Notes.html")
+	require.NoError(t, os.WriteFile(undatedPath, []byte("<div class='title'>Notes</div>"), 0o600))
+
+	var buf bytes.Buffer
+
+	cmd := newRootCmd(&buf)
+	cmd.SetOut(&buf)
+	cmd.SetArgs([]string{"convert", "-i", inputDir, "--dry-run"})
+
+	require.NoError(t, cmd.Execute())
+
+	entries, err := os.ReadDir(tmpDir)
+	require.NoError(t, err)
+	require.Len(t, entries, 1, "dry run must not write anything")
+
+	output := buf.String()
+	require.Contains(t, output, "Dry Run")
+	require.Contains(t, output, "2 (0 could not be parsed)")
+	require.Contains(t, output, "2025-12-15 – 2025-12-15")
+	require.Contains(t, output, "0 photos, 0 videos, 0 audio recordings (0 B)")
+	require.Contains(t, output, "1 entries have no detectable date")
+	require.Contains(t, output, undatedPath)
+	require.Contains(t, output, "nothing was written")
+}
+
+func TestRunConvertDryRunReportNeedsOutput(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	inputDir := filepath.Join(tmpDir, "input")
+
+	setupTestData(t, inputDir)
+
+	var buf bytes.Buffer
+
+	cfg := &appConfig{
+		inputPath:   inputDir,
+		journalName: "Test",
+		timeZone:    "UTC",
+		dryRun:      true,
+		writeReport: true,
+		output:      &buf,
+		log:         logger.New(&buf),
+	}
+
+	require.ErrorIs(t, runConvert(cfg), errReportNoOutput)
+}
+
+func TestPrintDryRunSummaryListsProblemAssets(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	report := &converter.Report{
+		Entries: []converter.EntryReport{{
+			Path: "Entries/a.html",
+			Assets: []converter.AssetReport{
+				{ID: "MAP", Type: "map", Status: converter.AssetSkipped, Reason: "map assets are not supported"},
+				{ID: "GONE", Type: "photo", Status: converter.AssetFailed, Reason: "resource file not found"},
+			},
+		}},
+		Totals: converter.ReportTotals{Entries: 1, SkippedAssets: 1, FailedAssets: 1},
+	}
+
+	printDryRunSummary(logger.New(&buf), report)
+
+	output := buf.String()
+	require.Contains(t, output, "1 assets would be skipped")
+	require.Contains(t, output, "Entries/a.html: map MAP: map assets are not supported")
+	require.Contains(t, output, "1 assets cannot be copied")
+	require.Contains(t, output, "Entries/a.html: photo GONE: resource file not found")
+}
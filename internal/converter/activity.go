@@ -0,0 +1,115 @@
+package converter
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/kpod13/journal2day1/internal/models"
+)
+
+const (
+	assetTypeActivity = "activity"
+	activityTagPrefix = "activity:"
+)
+
+// activityBlock renders an activity as a paragraph such as
+// "**Morning Walk:** 5,432 steps, 3.2 km, 45 min".
+func activityBlock(activity *models.MotionActivity) models.TextBlock {
+	name := activity.Label
+	if name == "" {
+		name = activityName(activity.Type)
+	}
+
+	var details []string
+
+	if activity.Steps > 0 {
+		details = append(details, groupThousands(activity.Steps)+" steps")
+	}
+
+	if activity.Distance > 0 {
+		details = append(details, formatDistance(activity.Distance))
+	}
+
+	if activity.Duration > 0 {
+		details = append(details, formatDuration(activity.Duration))
+	}
+
+	if len(details) == 0 {
+		return models.TextBlock{Runs: []models.TextRun{{Text: name, Style: models.TextStyle{Bold: true}}}}
+	}
+
+	return models.TextBlock{Runs: []models.TextRun{
+		{Text: name + ":", Style: models.TextStyle{Bold: true}},
+		{Text: " " + strings.Join(details, ", ")},
+	}}
+}
+
+// activityName turns an activity type such as "outdoor-walk" into
+// "Outdoor walk".
+func activityName(activityType string) string {
+	if activityType == "" {
+		return "Activity"
+	}
+
+	return capitalize(strings.ReplaceAll(activityType, "-", " "))
+}
+
+// capitalize upper-cases the first letter of a text.
+func capitalize(text string) string {
+	r, size := utf8.DecodeRuneInString(text)
+	if r == utf8.RuneError {
+		return text
+	}
+
+	return string(unicode.ToUpper(r)) + text[size:]
+}
+
+func groupThousands(n int) string {
+	digits := strconv.Itoa(n)
+
+	for i := len(digits) - 3; i > 0; i -= 3 {
+		digits = digits[:i] + "," + digits[i:]
+	}
+
+	return digits
+}
+
+func formatDistance(meters float64) string {
+	if meters < 1000 {
+		return fmt.Sprintf("%.0f m", meters)
+	}
+
+	return fmt.Sprintf("%.1f km", meters/1000)
+}
+
+func formatDuration(d time.Duration) string {
+	d = d.Round(time.Minute)
+
+	switch {
+	case d == 0:
+		return "less than a minute"
+	case d < time.Hour:
+		return fmt.Sprintf("%d min", int(d.Minutes()))
+	case d%time.Hour == 0:
+		return fmt.Sprintf("%d h", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%d h %d min", int(d.Hours()), int((d % time.Hour).Minutes()))
+	}
+}
+
+// activityTags returns the "activity:" tags of an entry's motion activities.
+func activityTags(entry *models.AppleJournalEntry) []string {
+	var tags []string
+
+	for _, asset := range entry.Assets {
+		if asset.Activity != nil && asset.Activity.Type != "" {
+			tags = append(tags, activityTagPrefix+asset.Activity.Type)
+		}
+	}
+
+	return tags
+}
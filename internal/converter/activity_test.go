@@ -0,0 +1,109 @@
+package converter_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/kpod13/journal2day1/internal/converter"
+)
+
+func setupActivityTestData(t *testing.T, inputDir string) {
+	t.Helper()
+
+	entriesDir := filepath.Join(inputDir, "Entries")
+	resourcesDir := filepath.Join(inputDir, "Resources")
+
+	require.NoError(t, os.MkdirAll(entriesDir, 0o750))
+	require.NoError(t, os.MkdirAll(resourcesDir, 0o750))
+
+	entry := `<div class="pageHeader">6 May 2024</div>
+<div class='title'>Busy day</div>
+<div class='bodyText'>Before breakfast</div>
+<div class="assetGrid">
+<div id="WALK" class="gridItem assetType_motionActivity"></div>
+<div id="PARK-PHOTO" class="gridItem assetType_photo"></div>
+</div>
+<div class='bodyText'>After lunch</div>
+<div class="assetGrid">
+<div id="RIDE" class="gridItem assetType_motionActivity" data-activity-type="Cycling">Ride to work</div>
+<div id="EMPTY" class="gridItem assetType_motionActivity"></div>
+</div>`
+
+	files := map[string]string{
+		"PARK-PHOTO.jpg": "photo",
+		"WALK.json":      `{"activityType": "walk", "steps": 5432, "distance": 3200, "duration": 2730}`,
+		"RIDE.json":      `{"distance": 850, "duration": 3900}`,
+	}
+
+	require.NoError(t, os.WriteFile(filepath.Join(entriesDir, "2024-05-06_Busy.html"), []byte(entry), 0o600))
+
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(resourcesDir, name), []byte(content), 0o600))
+	}
+}
+
+func TestConvertMotionActivities(t *testing.T) {
+	t.Parallel()
+
+	for _, withTags := range []bool{false, true} {
+		t.Run(map[bool]string{false: "without tags", true: "with tags"}[withTags], func(t *testing.T) {
+			t.Parallel()
+
+			tmpDir := t.TempDir()
+			inputDir := filepath.Join(tmpDir, "input")
+			outputPath := filepath.Join(tmpDir, "output.zip")
+
+			setupActivityTestData(t, inputDir)
+
+			conv := converter.NewConverter(inputDir, "Journal")
+			conv.SetActivityTags(withTags)
+			require.NoError(t, conv.Convert(outputPath))
+
+			entry := readExport(t, outputPath).Entries[0]
+			require.Equal(t, "# Busy day\n\nBefore breakfast\n"+
+				"**Walk:** 5,432 steps, 3.2 km, 46 min\n\n"+
+				"![](dayone-moment://PARKPHOTO)\n\n"+
+				"After lunch\n"+
+				"**Ride to work:** 850 m, 1 h 5 min", entry.Text)
+			require.Contains(t, entry.RichText, "5,432 steps")
+
+			if withTags {
+				require.Equal(t, []string{"activity:walk", "activity:cycling"}, entry.Tags)
+			} else {
+				require.Empty(t, entry.Tags)
+			}
+
+			assets := conv.Report().Entries[0].Assets
+			require.Equal(t, converter.AssetConverted, assets[0].Status)
+			require.Equal(t, "activity", assets[0].Kind)
+			require.Equal(t, converter.AssetSkipped, assets[3].Status)
+			require.Equal(t, "motion activity asset has no data", assets[3].Reason)
+		})
+	}
+}
+
+func TestConvertMotionActivityNonASCIIType(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	inputDir := filepath.Join(tmpDir, "input")
+	outputPath := filepath.Join(tmpDir, "output.zip")
+
+	require.NoError(t, os.MkdirAll(filepath.Join(inputDir, "Entries"), 0o750))
+	require.NoError(t, os.MkdirAll(filepath.Join(inputDir, "Resources"), 0o750))
+
+	entry := `<div class="pageHeader">6 May 2024</div>
+<div class="assetGrid"><div id="SKI" class="gridItem assetType_motionActivity"></div></div>`
+
+	require.NoError(t, os.WriteFile(filepath.Join(inputDir, "Entries", "2024-05-06_Ski.html"), []byte(entry), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(inputDir, "Resources", "SKI.json"),
+		[]byte(`{"activityType": "ésquí de fondo", "distance": 12000}`), 0o600))
+
+	conv := converter.NewConverter(inputDir, "Journal")
+	require.NoError(t, conv.Convert(outputPath))
+
+	require.Equal(t, "**Ésquí de fondo:** 12.0 km", readExport(t, outputPath).Entries[0].Text)
+}
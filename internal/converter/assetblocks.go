@@ -0,0 +1,62 @@
+package converter
+
+import (
+	"slices"
+
+	"github.com/kpod13/journal2day1/internal/models"
+	"github.com/kpod13/journal2day1/internal/parser"
+)
+
+// withAssetBlocks returns the entry with a paragraph in place of each asset
+// whose content is rendered as text, such as a motion activity or a mood.
+// Other entries are returned unchanged.
+func withAssetBlocks(entry *models.AppleJournalEntry) *models.AppleJournalEntry {
+	if !slices.ContainsFunc(entry.Assets, func(asset models.AppleJournalAsset) bool {
+		_, ok := assetBlock(&asset)
+
+		return ok
+	}) {
+		return entry
+	}
+
+	expanded := *entry
+	expanded.Blocks = slices.Clone(entry.Blocks)
+	expanded.Assets = slices.Clone(entry.Assets)
+
+	if len(entry.Blocks) == 0 && entry.Body != "" {
+		for i := range entry.Assets {
+			if block, ok := assetBlock(&entry.Assets[i]); ok {
+				expanded.Body += "\n\n" + parser.RenderMarkdown([]models.TextBlock{block})
+			}
+		}
+
+		return &expanded
+	}
+
+	inserted := 0
+
+	for i := range expanded.Assets {
+		asset := &expanded.Assets[i]
+		asset.Position += inserted
+
+		if block, ok := assetBlock(asset); ok {
+			expanded.Blocks = slices.Insert(expanded.Blocks, asset.Position, block)
+			inserted++
+		}
+	}
+
+	return &expanded
+}
+
+// assetBlock renders an asset as a paragraph, or reports false for assets
+// that are not rendered as text.
+func assetBlock(asset *models.AppleJournalAsset) (models.TextBlock, bool) {
+	switch {
+	case asset.Activity != nil:
+		return activityBlock(asset.Activity), true
+	case asset.Mood != nil:
+		return moodBlock(asset.Mood)
+	default:
+		return models.TextBlock{}, false
+	}
+}
@@ -0,0 +1,71 @@
+package converter_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/kpod13/journal2day1/internal/converter"
+)
+
+func setupAudioTestData(t *testing.T, inputDir string) {
+	t.Helper()
+
+	entriesDir := filepath.Join(inputDir, "Entries")
+	resourcesDir := filepath.Join(inputDir, "Resources")
+
+	require.NoError(t, os.MkdirAll(entriesDir, 0o750))
+	require.NoError(t, os.MkdirAll(resourcesDir, 0o750))
+
+	entry := `<div class="pageHeader">6 May 2024</div>
+<div class='title'>Voice notes</div>
+<div class="assetGrid"><div id="MEMO-1" class="gridItem assetType_audio"></div></div>
+<div class='bodyText'>Recorded on the train</div>
+<div class="assetGrid"><div id="MEMO-2" class="gridItem assetType_audio"></div></div>`
+
+	require.NoError(t, os.WriteFile(filepath.Join(entriesDir, "2024-05-06_Voice.html"), []byte(entry), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(resourcesDir, "MEMO-1.m4a"), minimalMovie(7), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(resourcesDir, "MEMO-2.mp3"), []byte("not really mp3"), 0o600))
+}
+
+func TestConvertAudio(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	inputDir := filepath.Join(tmpDir, "input")
+	outputPath := filepath.Join(tmpDir, "output.zip")
+
+	setupAudioTestData(t, inputDir)
+
+	conv := converter.NewConverter(inputDir, "Journal")
+	require.NoError(t, conv.Convert(outputPath))
+
+	entry := readExport(t, outputPath).Entries[0]
+	require.Empty(t, entry.Photos, "recordings are not photos")
+	require.Len(t, entry.Audios, 2)
+
+	memo, unreadable := entry.Audios[0], entry.Audios[1]
+	require.Equal(t, "MEMO1", memo.Identifier)
+	require.Equal(t, "m4a", memo.Format)
+	require.InDelta(t, 7.0, memo.Duration, 1e-9)
+	require.Equal(t, "mp3", unreadable.Format)
+	require.Zero(t, unreadable.Duration, "unreadable headers leave the duration unset")
+
+	require.Equal(t, "# Voice notes\n\n![](dayone-moment:/audio/MEMO1)\n\nRecorded on the train\n\n"+
+		"![](dayone-moment:/audio/MEMO2)", entry.Text)
+	require.Contains(t, entry.RichText, `{"type":"audio","identifier":"MEMO1"}`)
+
+	files := zipMediaFiles(t, outputPath)
+	require.Len(t, files, 2)
+
+	for _, file := range files {
+		require.True(t, strings.HasPrefix(file, "audios/"), file)
+	}
+
+	report := conv.Report()
+	require.Equal(t, "audio", report.Entries[0].Assets[0].Kind)
+	require.Equal(t, 2, report.Totals.Audios)
+}
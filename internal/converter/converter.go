@@ -8,16 +8,22 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"maps"
+	"math"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/pkg/errors"
 
+	"github.com/kpod13/journal2day1/internal/media"
 	"github.com/kpod13/journal2day1/internal/models"
 	"github.com/kpod13/journal2day1/internal/parser"
+	"github.com/kpod13/journal2day1/internal/workpool"
 )
 
 const (
@@ -27,23 +33,52 @@ const (
 	filePermission = 0o600
 )
 
+// entryNamespace scopes the name-based UUIDs of converted entries.
+var entryNamespace = uuid.NewSHA1(uuid.NameSpaceURL, []byte("https://github.com/kpod13/journal2day1/entry"))
+
 // ProgressFunc is called during conversion to report progress.
 type ProgressFunc func(current, total int)
 
 // Converter converts Apple Journal entries to DayOne format.
 type Converter struct {
-	parser      *parser.AppleJournalParser
-	journalName string
-	timeZone    string
-	onProgress  ProgressFunc
+	inputPath     string
+	parser        *parser.AppleJournalParser
+	journalName   string
+	timeZone      string
+	locale        string
+	mediaAtEnd    bool
+	jobs          int
+	lenient       bool
+	dryRun        bool
+	randomUUIDs   bool
+	statePath     string
+	filter        *Filter
+	split         Split
+	routes        []Route
+	tags          []string
+	tagRules      []TagRule
+	stripTags     bool
+	activityTags  bool
+	moodTags      []string
+	entryLocation string
+	livePhotos    string
+	knownUUIDs    map[string]string // by entry file name, from the state file
+	excluded      map[string]int
+	onProgress    ProgressFunc
+	orphans       []string
+	failures      []parser.EntryError
+	report        *Report
 }
 
-// NewConverter creates a new converter.
+// NewConverter creates a new converter. The Apple Journal path may be an
+// export directory or a ZIP or tar.gz archive of one.
 func NewConverter(appleJournalPath, journalName string) *Converter {
 	return &Converter{
-		parser:      parser.NewAppleJournalParser(appleJournalPath),
-		journalName: journalName,
-		timeZone:    "Europe/Sofia",
+		inputPath:     appleJournalPath,
+		journalName:   journalName,
+		timeZone:      "Europe/Sofia",
+		entryLocation: EntryLocationFirst,
+		livePhotos:    LivePhotoStill,
 	}
 }
 
@@ -52,102 +87,417 @@ func (c *Converter) SetTimeZone(tz string) {
 	c.timeZone = tz
 }
 
-// SetProgressFunc sets the progress callback function.
+// SetLocale sets the locale of the export's page header dates instead of
+// detecting it.
+func (c *Converter) SetLocale(name string) error {
+	if _, err := parser.LookupLocale(name); err != nil {
+		return err
+	}
+
+	c.locale = name
+
+	return nil
+}
+
+// SetMediaAtEnd places all photos and videos after the entry text instead of
+// at their original position.
+func (c *Converter) SetMediaAtEnd(atEnd bool) {
+	c.mediaAtEnd = atEnd
+}
+
+// SetJobs sets how many entries are parsed and how many media files are
+// hashed and copied concurrently. Values below one work sequentially.
+func (c *Converter) SetJobs(jobs int) {
+	c.jobs = jobs
+}
+
+// SetLenient converts the remaining entries when some entries fail to parse,
+// instead of aborting. Skipped entries are listed by Failures.
+func (c *Converter) SetLenient(lenient bool) {
+	c.lenient = lenient
+}
+
+// Failures returns the entries skipped by the last lenient Convert.
+func (c *Converter) Failures() []parser.EntryError {
+	return c.failures
+}
+
+// SetRandomUUIDs gives entries random UUIDs. By default the UUID is derived
+// from the source entry, so converting the same export again yields the same
+// Day One entries.
+func (c *Converter) SetRandomUUIDs(random bool) {
+	c.randomUUIDs = random
+}
+
+// SetStatePath enables incremental conversion. The state file lists the
+// entries converted by earlier runs; only new or changed entries are
+// converted, and the file is updated once the ZIP has been written.
+func (c *Converter) SetStatePath(path string) {
+	c.statePath = path
+}
+
+// SetFilter converts only the entries that pass the filter. The report
+// counts the entries each rule excluded.
+func (c *Converter) SetFilter(filter Filter) {
+	c.filter = &filter
+}
+
+// SetRoutes sends entries to several journals of the archive. Entries that
+// match no route stay in the journal the converter was created with.
+func (c *Converter) SetRoutes(routes []Route) {
+	c.routes = routes
+}
+
+// SetTags adds fixed tags to every entry, e.g. to find migrated entries.
+func (c *Converter) SetTags(tags []string) {
+	c.tags = tags
+}
+
+// SetTagRules tags the entries matching each rule.
+func (c *Converter) SetTagRules(rules []TagRule) {
+	c.tagRules = rules
+}
+
+// SetStripHashtags removes hashtags from the entry text once they are
+// converted to tags.
+func (c *Converter) SetStripHashtags(strip bool) {
+	c.stripTags = strip
+}
+
+// SetActivityTags tags entries with the type of their motion activities,
+// e.g. "activity:walk".
+func (c *Converter) SetActivityTags(enabled bool) {
+	c.activityTags = enabled
+}
+
+// SetSplit writes several archives instead of one, see Split.
+func (c *Converter) SetSplit(split Split) error {
+	if err := split.validate(); err != nil {
+		return err
+	}
+
+	c.split = split
+
+	return nil
+}
+
+// SetDryRun makes Convert parse the export and build the report without
+// copying media or writing the output archive.
+func (c *Converter) SetDryRun(dryRun bool) {
+	c.dryRun = dryRun
+}
+
+// SetProgressFunc sets the progress callback function. It is called with the
+// number of converted entries, one call at a time, even when several jobs
+// run concurrently.
 func (c *Converter) SetProgressFunc(fn ProgressFunc) {
 	c.onProgress = fn
 }
 
+// OrphanedResources returns the IDs of resources in the export that no entry
+// refers to. It is filled in by Convert.
+func (c *Converter) OrphanedResources() []string {
+	return c.orphans
+}
+
+// Report returns the report of the last Convert, or nil before the first one.
+func (c *Converter) Report() *Report {
+	return c.report
+}
+
 // Convert converts all Apple Journal entries and creates a DayOne ZIP archive.
+// In a dry run the output path is not used. With a state file only new or
+// changed entries are converted, and ErrNothingNew is returned when there
+// are none.
 func (c *Converter) Convert(outputPath string) error {
-	entries, err := c.parser.ParseAll()
+	var state *State
+
+	if c.statePath != "" {
+		loaded, err := LoadState(c.statePath)
+		if err != nil {
+			return err
+		}
+
+		state = loaded
+		c.knownUUIDs = state.uuids()
+	}
+
+	p, err := c.openParser()
 	if err != nil {
-		return errors.Wrap(err, "failed to parse entries")
+		return err
 	}
 
+	defer func() { _ = p.Close() }() //nolint:errcheck // cleanup errors are not critical
+
+	entries, err := c.parseEntries(p)
+	if err != nil {
+		return err
+	}
+
+	c.excluded = nil
+
+	if c.filter != nil {
+		entries, c.excluded = c.filter.apply(entries)
+	}
+
+	unchanged := 0
+
+	if state != nil {
+		entries, unchanged = selectChanged(entries, state)
+	}
+
+	if c.dryRun || (state != nil && len(entries) == 0) {
+		dayOneExport, entryReports := c.convertEntries(entries, nil)
+		archives := assignArchives(c.split.partition(outputPath, dayOneExport, entryReports), entryReports)
+		c.setReport(entryReports, archives, unchanged)
+
+		if !c.dryRun {
+			return ErrNothingNew
+		}
+
+		return nil
+	}
+
+	entryReports, archives, err := c.writeArchive(entries, outputPath)
+	if err != nil {
+		return err
+	}
+
+	c.setReport(entryReports, archives, unchanged)
+
+	if state == nil {
+		return nil
+	}
+
+	state.record(entries, entryReports, time.Now())
+
+	return state.Save(c.statePath)
+}
+
+func (c *Converter) setReport(entryReports []EntryReport, archives []string, unchanged int) {
+	c.report = newReport(entryReports, c.failures, c.orphans)
+	c.report.DryRun = c.dryRun
+	c.report.Archives = archives
+	c.report.Totals.UnchangedEntries = unchanged
+
+	for _, count := range c.excluded {
+		c.report.Totals.ExcludedEntries += count
+	}
+
+	if len(c.excluded) > 0 {
+		c.report.ExcludedEntries = c.excluded
+	}
+}
+
+func (c *Converter) openParser() (*parser.AppleJournalParser, error) {
+	p, err := parser.OpenExport(c.inputPath)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open export")
+	}
+
+	if c.locale != "" {
+		if err := p.SetLocale(c.locale); err != nil {
+			_ = p.Close() //nolint:errcheck // the locale error is more relevant
+
+			return nil, errors.Wrap(err, "failed to set locale")
+		}
+	}
+
+	p.SetJobs(c.jobs)
+	p.SetLenient(c.lenient)
+	c.parser = p
+
+	return p, nil
+}
+
+func (c *Converter) parseEntries(p *parser.AppleJournalParser) ([]models.AppleJournalEntry, error) {
+	entries, err := p.ParseAll()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse entries")
+	}
+
+	c.failures = p.Failures()
+
+	catalog, err := p.Resources()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to index resources")
+	}
+
+	c.orphans = catalog.Orphans(entries)
+
+	return entries, nil
+}
+
+// selectChanged drops the entries the state records as converted and
+// returns how many were dropped.
+func selectChanged(entries []models.AppleJournalEntry, state *State) ([]models.AppleJournalEntry, int) {
+	changed := make([]models.AppleJournalEntry, 0, len(entries))
+
+	for i := range entries {
+		if !state.IsConverted(&entries[i]) {
+			changed = append(changed, entries[i])
+		}
+	}
+
+	return changed, len(entries) - len(changed)
+}
+
+// writeArchive converts the entries and their media into one or more DayOne
+// ZIP archives and returns the archive file names.
+func (c *Converter) writeArchive(
+	entries []models.AppleJournalEntry,
+	outputPath string,
+) ([]EntryReport, []string, error) {
 	tmpDir, err := os.MkdirTemp("", "journal2day1-*")
 	if err != nil {
-		return errors.Wrap(err, "failed to create temp dir")
+		return nil, nil, errors.Wrap(err, "failed to create temp dir")
 	}
 
 	defer func() { _ = os.RemoveAll(tmpDir) }() //nolint:errcheck // cleanup errors are not critical
 
 	dirs, err := c.createOutputDirs(tmpDir)
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
 
-	dayOneExport := c.convertEntries(entries, dirs)
+	dayOneExport, entryReports := c.convertEntries(entries, dirs)
+	parts := c.split.partition(outputPath, dayOneExport, entryReports)
 
-	if err := c.writeJSON(tmpDir, dayOneExport); err != nil {
-		return err
+	for _, part := range parts {
+		if err := c.writePart(tmpDir, part, dayOneExport, entryReports); err != nil {
+			return nil, nil, err
+		}
 	}
 
-	return createZipArchive(tmpDir, outputPath)
+	return entryReports, assignArchives(parts, entryReports), nil
+}
+
+// writePart writes the archive of one part: a JSON file per journal and the
+// media its entries use.
+func (c *Converter) writePart(tmpDir string, part archivePart, export models.DayOneExport, reports []EntryReport) error {
+	journals := make(map[string]*models.DayOneExport)
+
+	var files []string
+
+	for _, i := range part.indexes {
+		journal, ok := journals[reports[i].Journal]
+		if !ok {
+			journal = &models.DayOneExport{Metadata: export.Metadata, Entries: []models.DayOneEntry{}}
+			journals[reports[i].Journal] = journal
+		}
+
+		journal.Entries = append(journal.Entries, export.Entries[i])
+
+		for _, asset := range reports[i].Assets {
+			if asset.File != "" && !slices.Contains(files, asset.File) {
+				files = append(files, asset.File)
+			}
+		}
+	}
+
+	if len(journals) == 0 {
+		journals[c.journalName] = &models.DayOneExport{Metadata: export.Metadata, Entries: []models.DayOneEntry{}}
+	}
+
+	documents := make([]zipDocument, 0, len(journals))
+
+	for _, name := range slices.Sorted(maps.Keys(journals)) {
+		jsonData, err := json.MarshalIndent(journals[name], "", "  ")
+		if err != nil {
+			return errors.Wrap(err, "failed to marshal JSON")
+		}
+
+		documents = append(documents, zipDocument{name: name + ".json", data: jsonData})
+	}
+
+	return createZipArchive(part.path, tmpDir, documents, files)
+}
+
+// assignArchives records the archive of each entry and returns the archive
+// file names in order.
+func assignArchives(parts []archivePart, reports []EntryReport) []string {
+	archives := make([]string, 0, len(parts))
+
+	for _, part := range parts {
+		if part.path == "" {
+			continue
+		}
+
+		name := filepath.Base(part.path)
+		archives = append(archives, name)
+
+		for _, i := range part.indexes {
+			reports[i].Archive = name
+		}
+	}
+
+	return archives
 }
 
 type outputDirs struct {
 	photos string
 	videos string
+	audios string
 }
 
 func (c *Converter) createOutputDirs(tmpDir string) (*outputDirs, error) {
 	dirs := &outputDirs{
 		photos: filepath.Join(tmpDir, "photos"),
 		videos: filepath.Join(tmpDir, "videos"),
+		audios: filepath.Join(tmpDir, "audios"),
 	}
 
-	if err := os.MkdirAll(dirs.photos, dirPermission); err != nil {
-		return nil, errors.Wrap(err, "failed to create photos dir")
-	}
-
-	if err := os.MkdirAll(dirs.videos, dirPermission); err != nil {
-		return nil, errors.Wrap(err, "failed to create videos dir")
+	for _, dir := range []string{dirs.photos, dirs.videos, dirs.audios} {
+		if err := os.MkdirAll(dir, dirPermission); err != nil {
+			return nil, errors.Wrapf(err, "failed to create %s dir", filepath.Base(dir))
+		}
 	}
 
 	return dirs, nil
 }
 
-func (c *Converter) convertEntries(entries []models.AppleJournalEntry, dirs *outputDirs) models.DayOneExport {
-	dayOneExport := models.DayOneExport{
-		Metadata: models.DayOneMetadata{Version: dayOneVersion},
-		Entries:  make([]models.DayOneEntry, 0, len(entries)),
-	}
-
+func (c *Converter) convertEntries(
+	entries []models.AppleJournalEntry,
+	dirs *outputDirs,
+) (models.DayOneExport, []EntryReport) {
+	converted := make([]models.DayOneEntry, len(entries))
+	reports := make([]EntryReport, len(entries))
 	total := len(entries)
 
-	for i := range entries {
-		if c.onProgress != nil {
-			c.onProgress(i+1, total)
-		}
+	var (
+		mu   sync.Mutex
+		done int
+	)
 
-		dayOneEntry := c.convertEntry(&entries[i], dirs)
-		dayOneExport.Entries = append(dayOneExport.Entries, *dayOneEntry)
-	}
+	workpool.ForEach(c.jobs, total, func(i int) {
+		dayOneEntry, report := c.convertEntry(&entries[i], dirs)
+		converted[i], reports[i] = *dayOneEntry, report
 
-	return dayOneExport
-}
+		if c.onProgress == nil {
+			return
+		}
 
-func (c *Converter) writeJSON(tmpDir string, export models.DayOneExport) error {
-	jsonPath := filepath.Join(tmpDir, c.journalName+".json")
+		mu.Lock()
+		defer mu.Unlock()
 
-	jsonData, err := json.MarshalIndent(export, "", "  ")
-	if err != nil {
-		return errors.Wrap(err, "failed to marshal JSON")
-	}
+		done++
+		c.onProgress(done, total)
+	})
 
-	if err := os.WriteFile(jsonPath, jsonData, filePermission); err != nil {
-		return errors.Wrap(err, "failed to write JSON")
+	dayOneExport := models.DayOneExport{
+		Metadata: models.DayOneMetadata{Version: dayOneVersion},
+		Entries:  converted,
 	}
 
-	return nil
+	return dayOneExport, reports
 }
 
-func (c *Converter) convertEntry(entry *models.AppleJournalEntry, dirs *outputDirs) *models.DayOneEntry {
+func (c *Converter) convertEntry(entry *models.AppleJournalEntry, dirs *outputDirs) (*models.DayOneEntry, EntryReport) {
 	now := time.Now().UTC().Format(iso8601Format)
 	creationDate := entry.Date.UTC().Format(iso8601Format)
 
 	dayOneEntry := &models.DayOneEntry{
-		UUID:           strings.ToUpper(strings.ReplaceAll(uuid.NewString(), "-", "")),
+		UUID:           c.entryUUID(entry),
 		CreationDate:   creationDate,
 		ModifiedDate:   now,
 		Starred:        false,
@@ -158,92 +508,274 @@ func (c *Converter) convertEntry(entry *models.AppleJournalEntry, dirs *outputDi
 		CreationDevice: "journal2day1",
 	}
 
-	photos, videos, photoRefs := c.processAssets(entry, dirs, creationDate)
-	dayOneEntry.Photos = photos
-	dayOneEntry.Videos = videos
-	dayOneEntry.Text = buildEntryText(entry, photoRefs)
+	dayOneEntry.Tags = c.entryTags(entry)
+	journal := journalFor(c.routes, entry, c.journalName)
+
+	if c.stripTags {
+		entry = stripHashtags(entry)
+	}
+
+	entry = withAssetBlocks(entry)
+	attached, assetReports := c.processAssets(entry, dirs, creationDate)
+
+	dayOneEntry.Photos = attached.photos
+	dayOneEntry.Videos = attached.videos
+	dayOneEntry.Audios = attached.audios
+	dayOneEntry.Location = c.chooseEntryLocation(entry, attached.photos)
+	entry = withPlacesNote(entry)
 
-	return dayOneEntry
+	// Media at the end follow every block, including the places note.
+	if c.mediaAtEnd {
+		for i := range attached.refs {
+			attached.refs[i].position = len(entry.Blocks)
+		}
+	}
+	dayOneEntry.Text = buildEntryText(entry, attached.refs)
+	dayOneEntry.RichText = buildRichText(entry, attached.refs)
+
+	report := EntryReport{
+		Path:    entry.FilePath,
+		UUID:    dayOneEntry.UUID,
+		Title:   entry.Title,
+		Date:    creationDate,
+		Undated: entry.Date.IsZero(),
+		Journal: journal,
+		Tags:    dayOneEntry.Tags,
+		Assets:  assetReports,
+	}
+
+	return dayOneEntry, report
+}
+
+// entryUUID returns the Day One UUID of an entry: the one an earlier run gave
+// it according to the state file, so that Day One updates an edited entry
+// instead of importing it again, or else random, or name-based over the
+// entry's file name, date and content hash.
+func (c *Converter) entryUUID(entry *models.AppleJournalEntry) string {
+	if known := c.knownUUIDs[filepath.Base(entry.FilePath)]; known != "" {
+		return known
+	}
+
+	id := uuid.New()
+
+	if !c.randomUUIDs {
+		name := strings.Join([]string{
+			filepath.Base(entry.FilePath),
+			entry.Date.UTC().Format(time.RFC3339),
+			entry.ContentHash,
+		}, "\x00")
+		id = uuid.NewSHA1(entryNamespace, []byte(name))
+	}
+
+	return strings.ToUpper(strings.ReplaceAll(id.String(), "-", ""))
+}
+
+// entryMedia holds the attachments of an entry and their references in the
+// entry text.
+type entryMedia struct {
+	photos []models.DayOnePhoto
+	videos []models.DayOneVideo
+	audios []models.DayOneAudio
+	refs   []mediaRef
 }
 
 func (c *Converter) processAssets(
 	entry *models.AppleJournalEntry,
 	dirs *outputDirs,
 	creationDate string,
-) ([]models.DayOnePhoto, []models.DayOneVideo, []string) {
+) (entryMedia, []AssetReport) {
 	var (
-		photos    []models.DayOnePhoto
-		videos    []models.DayOneVideo
-		photoRefs []string
+		attached entryMedia
+		reports  []AssetReport
 	)
 
-	for i, asset := range entry.Assets {
-		if shouldSkipAsset(asset.Type) {
-			continue
-		}
+	for _, asset := range entry.Assets {
+		if report, ok := entryDataReport(asset); ok {
+			reports = append(reports, report)
 
-		photo, video, ref := c.processAsset(asset, i, dirs, creationDate)
-		if photo != nil {
-			photos = append(photos, *photo)
-			photoRefs = append(photoRefs, ref)
+			continue
 		}
 
-		if video != nil {
-			videos = append(videos, *video)
+		for _, file := range c.assetFiles(asset) {
+			reports = append(reports, c.processAsset(asset, file, dirs, creationDate, &attached))
 		}
 	}
 
-	return photos, videos, photoRefs
+	return attached, reports
 }
 
-func shouldSkipAsset(assetType string) bool {
-	skipTypes := map[string]bool{
-		"map":         true,
-		"activity":    true,
-		"stateOfMind": true,
+// entryDataReport reports an asset whose content goes into the entry itself
+// instead of being attached, and whether the asset is of such a type. It is
+// skipped when the export holds nothing to convert.
+func entryDataReport(asset models.AppleJournalAsset) (AssetReport, bool) {
+	var (
+		kind, reason string
+		converted    bool
+	)
+
+	switch asset.Type {
+	case assetTypeMap:
+		kind, converted, reason = kindLocation, asset.Place != nil, "map asset has no place"
+	case assetTypeActivity:
+		kind, converted, reason = kindActivity, asset.Activity != nil, "motion activity asset has no data"
+	case assetTypeStateOfMind:
+		kind, converted, reason = kindMood, asset.Mood != nil, "state of mind asset has no data"
+	default:
+		return AssetReport{}, false
+	}
+
+	if !converted {
+		return AssetReport{ID: asset.ID, Type: asset.Type, Status: AssetSkipped, Reason: reason}, true
 	}
 
-	return skipTypes[assetType]
+	return AssetReport{ID: asset.ID, Type: asset.Type, Kind: kind, Status: AssetConverted}, true
 }
 
+// processAsset copies a file of a media asset and adds it to the entry's
+// attachments. Attachments are numbered in the order they are added.
 func (c *Converter) processAsset(
 	asset models.AppleJournalAsset,
-	order int,
+	file assetFile,
 	dirs *outputDirs,
 	creationDate string,
-) (*models.DayOnePhoto, *models.DayOneVideo, string) {
-	resourcePath := c.parser.GetResourceFilePath(asset.ID)
-	if resourcePath == "" {
-		return nil, nil, ""
+	attached *entryMedia,
+) AssetReport {
+	report := AssetReport{ID: asset.ID, Type: asset.Type, Status: AssetFailed}
+
+	if file.path == "" {
+		report.Reason = "resource file not found"
+
+		return report
 	}
 
-	md5Hash, fileSize, err := copyMediaFile(resourcePath, asset.Extension, dirs)
+	md5Hash, fileSize, err := c.stageMediaFile(file.path, file.ext, dirs)
 	if err != nil {
-		return nil, nil, ""
+		report.Reason = err.Error()
+
+		return report
 	}
 
-	assetDate := c.getAssetDate(asset.ID, creationDate)
-	identifier := strings.ToUpper(strings.ReplaceAll(asset.ID, "-", ""))
-	ext := strings.ToLower(asset.Extension)
+	report.Status = AssetCopied
+	report.Size = fileSize
+
+	if !c.dryRun {
+		report.File = zipMediaPath(file.ext, md5Hash, dirs)
+	}
 
-	if isVideoExtension(ext) {
+	meta := c.resourceMeta(asset.ID)
+	assetDate, hasSidecarDate := getAssetDate(meta, creationDate)
+	identifier, order := file.identifier, len(attached.refs)
+	ext := strings.ToLower(file.ext)
+	report.Kind = mediaKind(ext)
+
+	switch report.Kind {
+	case kindVideo:
 		video := createVideo(identifier, ext, md5Hash, fileSize, order, assetDate)
-		return nil, video, ""
+
+		if !c.dryRun {
+			setVideoMetadata(video, getDestinationPath(ext, md5Hash, dirs))
+		}
+
+		attached.videos = append(attached.videos, *video)
+	case kindAudio:
+		audio := createAudio(identifier, ext, md5Hash, fileSize, order, assetDate)
+
+		if !c.dryRun {
+			setAudioDuration(audio, getDestinationPath(ext, md5Hash, dirs))
+		}
+
+		attached.audios = append(attached.audios, *audio)
+	default:
+		photo := createPhoto(identifier, ext, md5Hash, fileSize, order, assetDate)
+		photo.Location = sidecarPhotoLocation(meta)
+
+		if !c.dryRun {
+			path := getDestinationPath(ext, md5Hash, dirs)
+			setPhotoDimensions(photo, path)
+			c.setPhotoEXIF(photo, path, !hasSidecarDate)
+		}
+
+		attached.photos = append(attached.photos, *photo)
 	}
 
-	photo := createPhoto(identifier, ext, md5Hash, fileSize, order, assetDate)
-	ref := fmt.Sprintf("![](dayone-moment://%s)", identifier)
+	attached.refs = append(attached.refs, mediaRef{kind: report.Kind, identifier: identifier, position: asset.Position})
 
-	return photo, nil, ref
+	return report
 }
 
-func (c *Converter) getAssetDate(assetID, fallbackDate string) string {
+// setPhotoDimensions reads the dimensions of a staged photo. They are
+// optional in Day One, so unreadable headers leave them unset.
+func setPhotoDimensions(photo *models.DayOnePhoto, path string) {
+	if dims, err := media.ImageFileDimensions(path); err == nil {
+		photo.Width, photo.Height = dims.Width, dims.Height
+	}
+}
+
+// setVideoMetadata reads the duration in seconds and the dimensions of a
+// staged video, leaving them unset for unreadable headers.
+func setVideoMetadata(video *models.DayOneVideo, path string) {
+	if info, err := media.VideoFileMetadata(path); err == nil {
+		video.Duration = int(math.Round(info.Duration.Seconds()))
+		video.Width, video.Height = info.Width, info.Height
+	}
+}
+
+// setAudioDuration reads the duration in seconds of a staged recording,
+// leaving it unset for unreadable headers.
+func setAudioDuration(audio *models.DayOneAudio, path string) {
+	if duration, err := media.AudioFileDuration(path); err == nil {
+		audio.Duration = math.Round(duration.Seconds()*1000) / 1000
+	}
+}
+
+// resourceMeta returns the sidecar metadata of an asset, or nil when it has
+// none or it cannot be read.
+func (c *Converter) resourceMeta(assetID string) *models.AppleJournalResourceMeta {
 	meta, err := c.parser.LoadResourceMeta(assetID)
-	if err != nil || meta.Date <= 0 {
-		return fallbackDate
+	if err != nil {
+		return nil
+	}
+
+	return meta
+}
+
+// getAssetDate returns the date of an asset from its sidecar metadata and
+// whether the sidecar had one, or the fallback date.
+func getAssetDate(meta *models.AppleJournalResourceMeta, fallbackDate string) (string, bool) {
+	if meta == nil || meta.Date <= 0 {
+		return fallbackDate, false
+	}
+
+	return models.CocoaTimestampToTime(meta.Date).UTC().Format(iso8601Format), true
+}
+
+// setPhotoEXIF fills in the camera of a staged photo from its EXIF metadata,
+// and its GPS position and capture date when the sidecar had none. Capture
+// times without an offset are read in the entry timezone.
+func (c *Converter) setPhotoEXIF(photo *models.DayOnePhoto, path string, useDate bool) {
+	exif, err := media.FileEXIF(path)
+	if err != nil {
+		return
 	}
 
-	return models.CocoaTimestampToTime(meta.Date).UTC().Format(iso8601Format)
+	if useDate {
+		loc, err := time.LoadLocation(c.timeZone)
+		if err != nil {
+			loc = time.UTC
+		}
+
+		if captured, ok := exif.CaptureTime(loc); ok {
+			photo.Date = captured.UTC().Format(iso8601Format)
+		}
+	}
+
+	if device := exif.Device(); device != "" {
+		photo.CreationDevice = device
+	}
+
+	if exif.HasLocation && photo.Location == nil {
+		photo.Location = &models.DayOnePhotoLocation{Latitude: exif.Latitude, Longitude: exif.Longitude}
+	}
 }
 
 func createPhoto(id, ext, md5Hash string, size int64, order int, date string) *models.DayOnePhoto {
@@ -275,95 +807,174 @@ func createVideo(id, ext, md5Hash string, size int64, order int, date string) *m
 	}
 }
 
-func buildEntryText(entry *models.AppleJournalEntry, photoRefs []string) string {
+func createAudio(id, ext, md5Hash string, size int64, order int, date string) *models.DayOneAudio {
+	return &models.DayOneAudio{
+		Identifier:     id,
+		Format:         normalizeExtension(ext),
+		MD5:            md5Hash,
+		FileSize:       size,
+		OrderInEntry:   order,
+		CreationDevice: "journal2day1",
+		Favorite:       false,
+		Date:           date,
+	}
+}
+
+// buildEntryText renders the entry as Markdown. Media references are placed
+// before the body block recorded in their position.
+func buildEntryText(entry *models.AppleJournalEntry, refs []mediaRef) string {
 	var textParts []string
 
 	if entry.Title != "" {
 		textParts = append(textParts, "# "+entry.Title)
 	}
 
-	if entry.Body != "" {
+	if len(entry.Blocks) == 0 && entry.Body != "" {
 		textParts = append(textParts, entry.Body)
 	}
 
-	if len(photoRefs) > 0 {
-		textParts = append(textParts, strings.Join(photoRefs, "\n"))
+	blocks := entry.Blocks
+	start, next := 0, 0
+
+	for i := 0; i <= len(blocks); i++ {
+		var links []string
+
+		for ; next < len(refs) && (refs[next].position <= i || i == len(blocks)); next++ {
+			links = append(links, momentLink(refs[next]))
+		}
+
+		if len(links) == 0 {
+			continue
+		}
+
+		if start < i {
+			textParts = append(textParts, parser.RenderMarkdown(blocks[start:i]))
+			start = i
+		}
+
+		textParts = append(textParts, strings.Join(links, "\n"))
+	}
+
+	if start < len(blocks) {
+		textParts = append(textParts, parser.RenderMarkdown(blocks[start:]))
 	}
 
 	return strings.Join(textParts, "\n\n")
 }
 
-func copyMediaFile(srcPath, ext string, dirs *outputDirs) (md5Hash string, fileSize int64, err error) {
-	src, err := os.Open(filepath.Clean(srcPath))
+// momentLink returns the Markdown reference Day One uses to display an attachment.
+func momentLink(ref mediaRef) string {
+	switch ref.kind {
+	case kindVideo:
+		return fmt.Sprintf("![](dayone-moment:/video/%s)", ref.identifier)
+	case kindAudio:
+		return fmt.Sprintf("![](dayone-moment:/audio/%s)", ref.identifier)
+	}
+
+	return fmt.Sprintf("![](dayone-moment://%s)", ref.identifier)
+}
+
+// stageMediaFile copies a resource into the output directory, or in a dry run
+// only determines its size.
+func (c *Converter) stageMediaFile(resourcePath, ext string, dirs *outputDirs) (md5Hash string, size int64, err error) {
+	if !c.dryRun {
+		return c.copyMediaFile(resourcePath, ext, dirs)
+	}
+
+	src, err := c.parser.OpenResource(resourcePath)
 	if err != nil {
 		return "", 0, errors.Wrap(err, "failed to open source")
 	}
 
 	defer func() { _ = src.Close() }() //nolint:errcheck // read-only file close errors are not critical
 
-	md5Hash, err = calculateMD5(src)
+	info, err := src.Stat()
 	if err != nil {
-		return "", 0, err
+		return "", 0, errors.Wrap(err, "failed to stat source")
 	}
 
-	stat, err := src.Stat()
+	return "", info.Size(), nil
+}
+
+// copyMediaFile copies a resource into the output directory for its type,
+// naming it by the MD5 of its contents, which is computed while copying.
+func (c *Converter) copyMediaFile(resourcePath, ext string, dirs *outputDirs) (md5Hash string, fileSize int64, err error) {
+	src, err := c.parser.OpenResource(resourcePath)
 	if err != nil {
-		return "", 0, errors.Wrap(err, "failed to get file stat")
+		return "", 0, errors.Wrap(err, "failed to open source")
 	}
 
-	if _, err := src.Seek(0, 0); err != nil {
-		return "", 0, errors.Wrap(err, "failed to seek file")
+	defer func() { _ = src.Close() }() //nolint:errcheck // read-only file close errors are not critical
+
+	tmpPath, md5Hash, fileSize, err := copyToTempFile(src, mediaDir(ext, dirs))
+	if err != nil {
+		return "", 0, err
 	}
 
-	dstPath := getDestinationPath(ext, md5Hash, dirs)
+	if err := os.Rename(tmpPath, getDestinationPath(ext, md5Hash, dirs)); err != nil {
+		_ = os.Remove(tmpPath) //nolint:errcheck // the rename error is more relevant
 
-	if err := copyToFile(src, dstPath); err != nil {
-		return "", 0, err
+		return "", 0, errors.Wrap(err, "failed to move media file")
 	}
 
-	return md5Hash, stat.Size(), nil
+	return md5Hash, fileSize, nil
 }
 
-func calculateMD5(r io.Reader) (string, error) {
+func copyToTempFile(src io.Reader, dir string) (tmpPath, md5Hash string, size int64, err error) {
+	dst, err := os.CreateTemp(dir, ".copy-*")
+	if err != nil {
+		return "", "", 0, errors.Wrap(err, "failed to create destination")
+	}
+
 	hash := md5.New() //nolint:gosec // MD5 is required by DayOne format specification
 
-	if _, err := io.Copy(hash, r); err != nil {
-		return "", errors.Wrap(err, "failed to calculate MD5")
+	size, err = io.Copy(dst, io.TeeReader(src, hash))
+	if cerr := dst.Close(); cerr != nil && err == nil {
+		err = errors.Wrap(cerr, "failed to close destination")
+	}
+
+	if err != nil {
+		_ = os.Remove(dst.Name()) //nolint:errcheck // the copy error is more relevant
+
+		return "", "", 0, errors.Wrap(err, "failed to copy file")
 	}
 
-	return hex.EncodeToString(hash.Sum(nil)), nil
+	return dst.Name(), hex.EncodeToString(hash.Sum(nil)), size, nil
 }
 
 func getDestinationPath(ext, md5Hash string, dirs *outputDirs) string {
 	normalizedExt := normalizeExtension(strings.ToLower(ext))
 
-	if isVideoExtension(ext) {
-		return filepath.Join(dirs.videos, md5Hash+"."+normalizedExt)
-	}
-
-	return filepath.Join(dirs.photos, md5Hash+"."+normalizedExt)
+	return filepath.Join(mediaDir(ext, dirs), md5Hash+"."+normalizedExt)
 }
 
-func copyToFile(src io.Reader, dstPath string) (err error) {
-	dst, err := os.Create(dstPath) //nolint:gosec // dstPath is constructed internally from trusted sources
-	if err != nil {
-		return errors.Wrap(err, "failed to create destination")
-	}
+// zipMediaPath returns the path of a copied media file within the output ZIP.
+func zipMediaPath(ext, md5Hash string, dirs *outputDirs) string {
+	dst := getDestinationPath(ext, md5Hash, dirs)
 
-	defer func() {
-		if cerr := dst.Close(); cerr != nil && err == nil {
-			err = errors.Wrap(cerr, "failed to close destination")
-		}
-	}()
+	return filepath.Base(filepath.Dir(dst)) + "/" + filepath.Base(dst)
+}
 
-	if _, err := io.Copy(dst, src); err != nil {
-		return errors.Wrap(err, "failed to copy file")
+func mediaDir(ext string, dirs *outputDirs) string {
+	switch mediaKind(ext) {
+	case kindVideo:
+		return dirs.videos
+	case kindAudio:
+		return dirs.audios
 	}
 
-	return nil
+	return dirs.photos
 }
 
-func createZipArchive(srcDir, dstPath string) (err error) {
+// zipDocument is a file written to a ZIP archive from memory.
+type zipDocument struct {
+	name string
+	data []byte
+}
+
+// createZipArchive writes the journal JSON documents and the given media
+// files, named relative to srcDir, into a new ZIP archive.
+func createZipArchive(dstPath, srcDir string, documents []zipDocument, files []string) (err error) {
 	zipFile, err := os.Create(dstPath) //nolint:gosec // dstPath is validated user input from CLI flags
 	if err != nil {
 		return errors.Wrap(err, "failed to create ZIP file")
@@ -383,23 +994,36 @@ func createZipArchive(srcDir, dstPath string) (err error) {
 		}
 	}()
 
-	return filepath.Walk(srcDir, func(path string, info os.FileInfo, err error) error {
+	for _, document := range documents {
+		writer, err := archive.CreateHeader(&zip.FileHeader{
+			Name:     document.name,
+			Method:   zip.Deflate,
+			Modified: time.Now(),
+		})
 		if err != nil {
-			return err
+			return errors.Wrap(err, "failed to create ZIP entry")
+		}
+
+		if _, err := writer.Write(document.data); err != nil {
+			return errors.Wrap(err, "failed to write to ZIP")
 		}
+	}
 
-		if info.IsDir() {
-			return nil
+	for _, name := range files {
+		if err := addFileToZip(archive, srcDir, name); err != nil {
+			return err
 		}
+	}
 
-		return addFileToZip(archive, srcDir, path, info)
-	})
+	return nil
 }
 
-func addFileToZip(archive *zip.Writer, srcDir, path string, info os.FileInfo) error {
-	relPath, err := filepath.Rel(srcDir, path)
+func addFileToZip(archive *zip.Writer, srcDir, name string) error {
+	path := filepath.Join(srcDir, filepath.FromSlash(name))
+
+	info, err := os.Stat(path)
 	if err != nil {
-		return errors.Wrap(err, "failed to get relative path")
+		return errors.Wrap(err, "failed to stat file for ZIP")
 	}
 
 	header, err := zip.FileInfoHeader(info)
@@ -407,7 +1031,7 @@ func addFileToZip(archive *zip.Writer, srcDir, path string, info os.FileInfo) er
 		return errors.Wrap(err, "failed to create ZIP header")
 	}
 
-	header.Name = relPath
+	header.Name = name
 	header.Method = zip.Deflate
 
 	writer, err := archive.CreateHeader(header)
@@ -429,15 +1053,25 @@ func addFileToZip(archive *zip.Writer, srcDir, path string, info os.FileInfo) er
 	return nil
 }
 
-func isVideoExtension(ext string) bool {
-	videoExts := map[string]bool{
-		"mov": true,
-		"mp4": true,
-		"m4v": true,
-		"avi": true,
+// mediaKind returns the Day One attachment kind of a media file by its
+// extension. Unknown extensions are treated as photos.
+func mediaKind(ext string) string {
+	kinds := map[string]string{
+		"mov": kindVideo,
+		"mp4": kindVideo,
+		"m4v": kindVideo,
+		"avi": kindVideo,
+		"m4a": kindAudio,
+		"aac": kindAudio,
+		"mp3": kindAudio,
+		"wav": kindAudio,
+	}
+
+	if kind, ok := kinds[strings.ToLower(ext)]; ok {
+		return kind
 	}
 
-	return videoExts[strings.ToLower(ext)]
+	return kindPhoto
 }
 
 func normalizeExtension(ext string) string {
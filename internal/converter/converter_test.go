@@ -2,7 +2,13 @@ package converter_test
 
 import (
 	"archive/zip"
+	"bytes"
+	"encoding/binary"
 	"encoding/json"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
 	"os"
 	"path/filepath"
 	"strings"
@@ -34,6 +40,150 @@ func TestConvert(t *testing.T) {
 	verifyZipContents(t, outputPath)
 }
 
+func TestConvertFromZipArchive(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	inputDir := filepath.Join(tmpDir, "AppleJournalEntries")
+	archivePath := filepath.Join(tmpDir, "export.zip")
+	outputPath := filepath.Join(tmpDir, "output.zip")
+
+	setupConvertTestData(t, inputDir)
+	zipDirectory(t, tmpDir, inputDir, archivePath)
+
+	conv := converter.NewConverter(archivePath, "TestJournal")
+
+	require.NoError(t, conv.Convert(outputPath))
+
+	verifyZipContents(t, outputPath)
+}
+
+func TestConvertReportsOrphanedResources(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	inputDir := filepath.Join(tmpDir, "input")
+	outputPath := filepath.Join(tmpDir, "output.zip")
+
+	setupConvertTestData(t, inputDir)
+
+	orphanPath := filepath.Join(inputDir, "Resources", "TEST-UUID-1234-5678-ABCD-2.jpg")
+	require.NoError(t, os.WriteFile(orphanPath, []byte("unreferenced"), 0o600))
+
+	conv := converter.NewConverter(inputDir, "TestJournal")
+
+	require.NoError(t, conv.Convert(outputPath))
+	require.Equal(t, []string{"TEST-UUID-1234-5678-ABCD-2"}, conv.OrphanedResources())
+
+	export := readExport(t, outputPath)
+	require.Len(t, export.Entries[0].Photos, 1)
+}
+
+func TestConvertLenientSkipsBrokenEntries(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	inputDir := filepath.Join(tmpDir, "input")
+	outputPath := filepath.Join(tmpDir, "output.zip")
+
+	setupMultipleEntriesData(t, inputDir)
+
+	brokenPath := filepath.Join(inputDir, "Entries", "2025-12-15_Broken.html")
+	require.NoError(t, os.Symlink(filepath.Join(tmpDir, "missing.html"), brokenPath))
+
+	strict := converter.NewConverter(inputDir, "Journal")
+	require.Error(t, strict.Convert(outputPath))
+	require.NoFileExists(t, outputPath)
+
+	lenient := converter.NewConverter(inputDir, "Journal")
+	lenient.SetLenient(true)
+
+	require.NoError(t, lenient.Convert(outputPath))
+	require.Len(t, readExport(t, outputPath).Entries, 3)
+	require.Len(t, lenient.Failures(), 1)
+	require.Equal(t, brokenPath, lenient.Failures()[0].Path)
+}
+
+func TestConvertDeterministicUUIDs(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	inputDir := filepath.Join(tmpDir, "input")
+
+	setupMultipleEntriesData(t, inputDir)
+
+	convert := func(name string, random bool) []string {
+		outputPath := filepath.Join(tmpDir, name+".zip")
+
+		conv := converter.NewConverter(inputDir, "Journal")
+		conv.SetRandomUUIDs(random)
+		require.NoError(t, conv.Convert(outputPath))
+
+		var uuids []string
+
+		for _, entry := range readExport(t, outputPath).Entries {
+			require.Len(t, entry.UUID, 32)
+			uuids = append(uuids, entry.UUID)
+		}
+
+		return uuids
+	}
+
+	first := convert("first", false)
+	require.Equal(t, first, convert("second", false))
+	require.NotEqual(t, first[0], first[1])
+
+	random := convert("random", true)
+	require.NotContains(t, first, random[0])
+	require.NotEqual(t, random, convert("random-again", true))
+
+	entryPath := filepath.Join(inputDir, "Entries", "2025-12-15_Entry_1.html")
+	require.NoError(t, os.WriteFile(entryPath, []byte("<div class='title'>Edited</div>"), 0o600))
+
+	edited := convert("edited", false)
+	require.NotEqual(t, first[0], edited[0])
+	require.Equal(t, first[1:], edited[1:])
+}
+
+// zipDirectory packs dir into a ZIP archive, naming files relative to base.
+func zipDirectory(t *testing.T, base, dir, archivePath string) {
+	t.Helper()
+
+	out, err := os.Create(archivePath)
+	require.NoError(t, err)
+
+	w := zip.NewWriter(out)
+
+	err = filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+
+		rel, err := filepath.Rel(base, path)
+		if err != nil {
+			return err
+		}
+
+		f, err := w.Create(filepath.ToSlash(rel))
+		if err != nil {
+			return err
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		_, err = f.Write(data)
+
+		return err
+	})
+
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	require.NoError(t, out.Close())
+}
+
 func TestSetTimeZone(t *testing.T) {
 	t.Parallel()
 
@@ -225,6 +375,72 @@ func TestConvertMultipleEntries(t *testing.T) {
 	verifyMultipleEntries(t, outputPath)
 }
 
+func TestConvertConcurrentJobs(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	inputDir := filepath.Join(tmpDir, "input")
+
+	setupConcurrentJobsTestData(t, inputDir, 20)
+
+	var (
+		progress []int
+		totals   []int
+	)
+
+	conv := converter.NewConverter(inputDir, "Journal")
+	conv.SetJobs(8)
+	conv.SetProgressFunc(func(current, total int) {
+		progress = append(progress, current)
+		totals = append(totals, total)
+	})
+
+	outputPath := filepath.Join(tmpDir, "output.zip")
+	require.NoError(t, conv.Convert(outputPath))
+
+	export := readExport(t, outputPath)
+	require.Len(t, export.Entries, 20)
+
+	for i, entry := range export.Entries {
+		require.Contains(t, entry.Text, fmt.Sprintf("# Entry %02d", i))
+		require.Len(t, entry.Photos, 1)
+		require.Equal(t, fmt.Sprintf("PHOTO%02d", i), entry.Photos[0].Identifier)
+	}
+
+	require.Len(t, progress, 20)
+
+	for i := range progress {
+		require.Equal(t, i+1, progress[i])
+		require.Equal(t, 20, totals[i])
+	}
+}
+
+func setupConcurrentJobsTestData(t *testing.T, inputDir string, count int) {
+	t.Helper()
+
+	entriesDir := filepath.Join(inputDir, "Entries")
+	resourcesDir := filepath.Join(inputDir, "Resources")
+
+	require.NoError(t, os.MkdirAll(entriesDir, 0o750))
+	require.NoError(t, os.MkdirAll(resourcesDir, 0o750))
+
+	for i := range count {
+		id := fmt.Sprintf("PHOTO-%02d", i)
+		htmlContent := fmt.Sprintf(`<!DOCTYPE html>
+<html>
+<body>
+<div class="pageHeader">Monday, 15 December 2025</div>
+<div class="assetGrid"><div id="%s" class="gridItem assetType_photo"></div></div>
+<div class='title'>Entry %02d</div>
+</body>
+</html>`, id, i)
+
+		entryPath := filepath.Join(entriesDir, fmt.Sprintf("2025-12-15_Entry_%02d.html", i))
+		require.NoError(t, os.WriteFile(entryPath, []byte(htmlContent), 0o600))
+		require.NoError(t, os.WriteFile(filepath.Join(resourcesDir, id+".jpg"), []byte(id), 0o600))
+	}
+}
+
 func setupMultipleEntriesData(t *testing.T, inputDir string) {
 	t.Helper()
 
@@ -1084,3 +1300,450 @@ func setupLargeImageTestData(t *testing.T, inputDir string) {
 
 	require.NoError(t, os.WriteFile(imgPath, largeData, 0o600))
 }
+
+func TestConvertRichText(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	inputDir := filepath.Join(tmpDir, "input")
+	outputPath := filepath.Join(tmpDir, "output.zip")
+
+	setupRichTextTestData(t, inputDir)
+
+	conv := converter.NewConverter(inputDir, "RichTextJournal")
+
+	require.NoError(t, conv.Convert(outputPath))
+
+	export := readExport(t, outputPath)
+	require.Len(t, export.Entries, 1)
+	require.NotEmpty(t, export.Entries[0].RichText)
+
+	var richText models.DayOneRichText
+
+	require.NoError(t, json.Unmarshal([]byte(export.Entries[0].RichText), &richText))
+	require.Equal(t, 1, richText.Meta.Version)
+
+	contents := richText.Contents
+	require.Len(t, contents, 6)
+
+	require.Equal(t, "Rich Entry\n", contents[0].Text)
+	require.Equal(t, 1, contents[0].Attributes.Line.Header)
+
+	require.Equal(t, "Hello ", contents[1].Text)
+	require.Nil(t, contents[1].Attributes)
+	require.Equal(t, "world\n", contents[2].Text)
+	require.True(t, contents[2].Attributes.Bold)
+
+	require.Len(t, contents[3].EmbeddedObjects, 1)
+	require.Equal(t, "photo", contents[3].EmbeddedObjects[0].Type)
+	require.Equal(t, "RICHPHOTOUUID", contents[3].EmbeddedObjects[0].Identifier)
+
+	require.Equal(t, "First\n", contents[4].Text)
+	require.Equal(t, "numbered", contents[4].Attributes.Line.ListStyle)
+	require.Equal(t, 1, contents[4].Attributes.Line.IndentLevel)
+	require.Equal(t, 1, contents[4].Attributes.Line.ListIndex)
+
+	require.Equal(t, "link\n", contents[5].Text)
+	require.Equal(t, "https://example.com", contents[5].Attributes.LinkURL)
+	require.True(t, contents[5].Attributes.Line.Quote)
+}
+
+func setupRichTextTestData(t *testing.T, inputDir string) {
+	t.Helper()
+
+	entriesDir := filepath.Join(inputDir, "Entries")
+	resourcesDir := filepath.Join(inputDir, "Resources")
+
+	require.NoError(t, os.MkdirAll(entriesDir, 0o750))
+	require.NoError(t, os.MkdirAll(resourcesDir, 0o750))
+
+	htmlContent := `<!DOCTYPE html>
+<html>
+<body>
+<div class="pageHeader">Monday, 15 December 2025</div>
+<div class='title'>Rich Entry</div>
+<div class='bodyText'><p>Hello <b>world</b></p></div>
+<div class="assetGrid">
+    <div id="RICH-PHOTO-UUID" class="gridItem assetType_photo">
+        <img src="../Resources/RICH-PHOTO-UUID.jpg" class="asset_image"/>
+    </div>
+</div>
+<div class='bodyText'><ol><li>First</li></ol><blockquote><a href="https://example.com">link</a></blockquote></div>
+</body>
+</html>`
+
+	entryPath := filepath.Join(entriesDir, "2025-12-15_Rich.html")
+
+	require.NoError(t, os.WriteFile(entryPath, []byte(htmlContent), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(resourcesDir, "RICH-PHOTO-UUID.jpg"), []byte("fake jpg"), 0o600))
+}
+
+// Markdown has no underline syntax: the entry text keeps underlined words
+// plain, and only the rich text carries the underline.
+func TestConvertUnderline(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	inputDir := filepath.Join(tmpDir, "input")
+	outputPath := filepath.Join(tmpDir, "output.zip")
+	entriesDir := filepath.Join(inputDir, "Entries")
+
+	htmlContent := `<!DOCTYPE html>
+<html>
+<body>
+<div class="pageHeader">Monday, 15 December 2025</div>
+<div class='bodyText'><p>Read <u>this</u> twice</p></div>
+</body>
+</html>`
+
+	require.NoError(t, os.MkdirAll(entriesDir, 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join(entriesDir, "2025-12-15_Underline.html"), []byte(htmlContent), 0o600))
+
+	require.NoError(t, converter.NewConverter(inputDir, "UnderlineJournal").Convert(outputPath))
+
+	export := readExport(t, outputPath)
+	require.Len(t, export.Entries, 1)
+	require.Equal(t, "Read this twice", export.Entries[0].Text)
+
+	var richText models.DayOneRichText
+
+	require.NoError(t, json.Unmarshal([]byte(export.Entries[0].RichText), &richText))
+	require.Len(t, richText.Contents, 3)
+	require.Equal(t, "this", richText.Contents[1].Text)
+	require.True(t, richText.Contents[1].Attributes.Underline)
+}
+
+func readExport(t *testing.T, zipPath string) models.DayOneExport {
+	t.Helper()
+
+	zipReader, err := zip.OpenReader(zipPath)
+	require.NoError(t, err)
+
+	defer func() { _ = zipReader.Close() }() //nolint:errcheck // test cleanup
+
+	var jsonFile *zip.File
+
+	for _, f := range zipReader.File {
+		if strings.HasSuffix(f.Name, ".json") {
+			jsonFile = f
+
+			break
+		}
+	}
+
+	require.NotNil(t, jsonFile)
+
+	rc, err := jsonFile.Open()
+	require.NoError(t, err)
+
+	defer func() { _ = rc.Close() }() //nolint:errcheck // test cleanup
+
+	var export models.DayOneExport
+
+	require.NoError(t, json.NewDecoder(rc).Decode(&export))
+
+	return export
+}
+
+func TestConvertInlineMedia(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name       string
+		mediaAtEnd bool
+		expected   string
+	}{
+		{
+			name: "media at original position",
+			expected: "# Inline Entry\n\nBefore\n\n" +
+				"![](dayone-moment://INLINEPHOTO)\n![](dayone-moment:/video/INLINEVIDEO)\n\nAfter",
+		},
+		{
+			name:       "media at end",
+			mediaAtEnd: true,
+			expected: "# Inline Entry\n\nBefore\nAfter\n\n" +
+				"![](dayone-moment://INLINEPHOTO)\n![](dayone-moment:/video/INLINEVIDEO)",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			tmpDir := t.TempDir()
+			inputDir := filepath.Join(tmpDir, "input")
+			outputPath := filepath.Join(tmpDir, "output.zip")
+
+			setupInlineMediaTestData(t, inputDir)
+
+			conv := converter.NewConverter(inputDir, "InlineJournal")
+			conv.SetMediaAtEnd(tc.mediaAtEnd)
+
+			require.NoError(t, conv.Convert(outputPath))
+
+			export := readExport(t, outputPath)
+			require.Len(t, export.Entries, 1)
+			require.Equal(t, tc.expected, export.Entries[0].Text)
+		})
+	}
+}
+
+func setupInlineMediaTestData(t *testing.T, inputDir string) {
+	t.Helper()
+
+	entriesDir := filepath.Join(inputDir, "Entries")
+	resourcesDir := filepath.Join(inputDir, "Resources")
+
+	require.NoError(t, os.MkdirAll(entriesDir, 0o750))
+	require.NoError(t, os.MkdirAll(resourcesDir, 0o750))
+
+	htmlContent := `<!DOCTYPE html>
+<html>
+<body>
+<div class="pageHeader">Monday, 15 December 2025</div>
+<div class='title'>Inline Entry</div>
+<div class='bodyText'><p>Before</p></div>
+<div class="assetGrid">
+    <div id="INLINE-PHOTO" class="gridItem assetType_photo">
+        <img src="../Resources/INLINE-PHOTO.jpg" class="asset_image"/>
+    </div>
+    <div id="INLINE-VIDEO" class="gridItem assetType_video">
+        <video src="../Resources/INLINE-VIDEO.mov" class="asset_video"></video>
+    </div>
+</div>
+<div class='bodyText'><p>After</p></div>
+</body>
+</html>`
+
+	entryPath := filepath.Join(entriesDir, "2025-12-15_Inline.html")
+
+	require.NoError(t, os.WriteFile(entryPath, []byte(htmlContent), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(resourcesDir, "INLINE-PHOTO.jpg"), []byte("fake jpg"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(resourcesDir, "INLINE-VIDEO.mov"), []byte("fake mov"), 0o600))
+}
+
+func TestConvertPhotoDimensions(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	inputDir := filepath.Join(tmpDir, "input")
+	outputPath := filepath.Join(tmpDir, "output.zip")
+
+	setupConvertTestData(t, inputDir)
+
+	var buf bytes.Buffer
+
+	require.NoError(t, png.Encode(&buf, image.NewGray(image.Rect(0, 0, 30, 20))))
+	require.NoError(t, os.WriteFile(filepath.Join(inputDir, "Resources", "PNG-UUID.png"), buf.Bytes(), 0o600))
+
+	entry := `<div class="pageHeader">16 December 2025</div>
+<div class="assetGrid"><div id="PNG-UUID" class="gridItem assetType_photo"></div></div>
+<div class='title'>Screenshot</div>`
+	require.NoError(t, os.WriteFile(filepath.Join(inputDir, "Entries", "2025-12-16_Screenshot.html"), []byte(entry), 0o600))
+
+	conv := converter.NewConverter(inputDir, "Journal")
+	require.NoError(t, conv.Convert(outputPath))
+
+	var photo *models.DayOnePhoto
+
+	for _, entry := range readExport(t, outputPath).Entries {
+		for i := range entry.Photos {
+			if entry.Photos[i].Identifier == "PNGUUID" {
+				photo = &entry.Photos[i]
+			}
+		}
+	}
+
+	require.NotNil(t, photo)
+	require.Equal(t, 30, photo.Width)
+	require.Equal(t, 20, photo.Height)
+}
+
+// minimalMovie returns a QuickTime file with a movie header of the given
+// length in seconds and one video track of 1280x720.
+func minimalMovie(seconds uint32) []byte {
+	box := func(typ string, payload ...[]byte) []byte {
+		data := bytes.Join(payload, nil)
+		out := binary.BigEndian.AppendUint32(nil, uint32(len(data)+8)) //nolint:gosec // test data is small
+
+		return append(append(out, typ...), data...)
+	}
+
+	words := func(values ...uint32) []byte {
+		var out []byte
+
+		for _, v := range values {
+			out = binary.BigEndian.AppendUint32(out, v)
+		}
+
+		return out
+	}
+
+	mvhd := box("mvhd", words(0, 0, 0, 1000, seconds*1000), make([]byte, 80))
+	matrix := words(0x10000, 0, 0, 0, 0x10000, 0, 0, 0, 0x40000000)
+	tkhd := box("tkhd", words(0, 0, 0, 1, 0, 0), make([]byte, 16), matrix, words(1280<<16, 720<<16))
+
+	return append(box("ftyp", []byte("qt  "), words(0)), box("moov", mvhd, box("trak", tkhd))...)
+}
+
+func TestConvertVideoMetadata(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	inputDir := filepath.Join(tmpDir, "input")
+	outputPath := filepath.Join(tmpDir, "output.zip")
+
+	setupVideoTestData(t, inputDir)
+
+	matches, err := filepath.Glob(filepath.Join(inputDir, "Resources", "*.mov"))
+	require.NoError(t, err)
+	require.Len(t, matches, 1)
+	require.NoError(t, os.WriteFile(matches[0], minimalMovie(42), 0o600))
+
+	conv := converter.NewConverter(inputDir, "Journal")
+	require.NoError(t, conv.Convert(outputPath))
+
+	entries := readExport(t, outputPath).Entries
+	require.Len(t, entries, 1)
+	require.Len(t, entries[0].Videos, 1)
+
+	video := entries[0].Videos[0]
+	require.Equal(t, 42, video.Duration)
+	require.Equal(t, 1280, video.Width)
+	require.Equal(t, 720, video.Height)
+}
+
+// exifJPEG returns a JPEG taken by an iPhone in Rome on 4 May 2024 at 10:11
+// local time, according to its EXIF metadata.
+func exifJPEG(t *testing.T) []byte {
+	t.Helper()
+
+	type tag struct {
+		id, typ uint16
+		count   uint32
+		value   []byte
+	}
+
+	le := binary.LittleEndian
+	ascii := func(id uint16, s string) tag { return tag{id, 2, uint32(len(s) + 1), append([]byte(s), 0)} } //nolint:gosec // short
+	long := func(id uint16, v uint32) tag { return tag{id, 4, 1, le.AppendUint32(nil, v)} }
+	dms := func(id uint16, d, m, s uint32) tag {
+		return tag{id, 5, 3, le.AppendUint32(le.AppendUint32(le.AppendUint32(le.AppendUint32(
+			le.AppendUint32(le.AppendUint32(nil, d), 1), m), 1), s), 1)}
+	}
+
+	// IFD0 at 8 (3 tags, 42 bytes), Exif IFD at 50 (2 tags, 30 bytes), GPS
+	// IFD at 80 (4 tags, 54 bytes), values from 134.
+	ifds := [][]tag{
+		{ascii(0x0110, "iPhone 15 Pro"), long(0x8769, 50), long(0x8825, 80)},
+		{ascii(0x9003, "2024:05:04 10:11:12"), ascii(0x9011, "+02:00")},
+		{ascii(0x0001, "N"), dms(0x0002, 41, 54, 36), ascii(0x0003, "E"), dms(0x0004, 12, 30, 0)},
+	}
+
+	tiff := append([]byte("II*\x00"), le.AppendUint32(nil, 8)...)
+	valueOffset := uint32(134)
+
+	var values []byte
+
+	for _, ifd := range ifds {
+		tiff = le.AppendUint16(tiff, uint16(len(ifd))) //nolint:gosec // short
+
+		for _, tg := range ifd {
+			tiff = le.AppendUint32(le.AppendUint16(le.AppendUint16(tiff, tg.id), tg.typ), tg.count)
+
+			if len(tg.value) <= 4 {
+				tiff = append(tiff, append(tg.value, make([]byte, 4-len(tg.value))...)...)
+
+				continue
+			}
+
+			tiff = le.AppendUint32(tiff, valueOffset+uint32(len(values))) //nolint:gosec // short
+			values = append(values, tg.value...)
+		}
+
+		tiff = le.AppendUint32(tiff, 0)
+	}
+
+	tiff = append(tiff, values...)
+	require.Len(t, tiff, int(valueOffset)+len(values), "directory offsets")
+
+	var buf bytes.Buffer
+
+	require.NoError(t, jpeg.Encode(&buf, image.NewGray(image.Rect(0, 0, 8, 6)), nil))
+
+	app1 := append([]byte("Exif\x00\x00"), tiff...)
+	segment := binary.BigEndian.AppendUint16([]byte{0xFF, 0xE1}, uint16(len(app1)+2)) //nolint:gosec // short
+
+	return bytes.Join([][]byte{buf.Bytes()[:2], segment, app1, buf.Bytes()[2:]}, nil)
+}
+
+func TestConvertPhotoEXIF(t *testing.T) {
+	t.Parallel()
+
+	for _, withSidecar := range []bool{false, true} {
+		t.Run(fmt.Sprintf("sidecar %v", withSidecar), func(t *testing.T) {
+			t.Parallel()
+
+			tmpDir := t.TempDir()
+			inputDir := filepath.Join(tmpDir, "input")
+			outputPath := filepath.Join(tmpDir, "output.zip")
+
+			setupConvertTestData(t, inputDir)
+
+			resources := filepath.Join(inputDir, "Resources")
+			matches, err := filepath.Glob(filepath.Join(resources, "*.jpg"))
+			require.NoError(t, err)
+			require.Len(t, matches, 1)
+			require.NoError(t, os.WriteFile(matches[0], exifJPEG(t), 0o600))
+
+			sidecar := strings.TrimSuffix(matches[0], ".jpg") + ".json"
+			if withSidecar {
+				require.NoError(t, os.WriteFile(sidecar, []byte(`{"date": 736000000}`), 0o600))
+			} else {
+				require.NoError(t, os.RemoveAll(sidecar))
+			}
+
+			conv := converter.NewConverter(inputDir, "Journal")
+			require.NoError(t, conv.Convert(outputPath))
+
+			photo := readExport(t, outputPath).Entries[0].Photos[0]
+			require.Equal(t, "iPhone 15 Pro", photo.CreationDevice)
+			require.NotNil(t, photo.Location)
+			require.InDelta(t, 41.91, photo.Location.Latitude, 1e-9)
+			require.InDelta(t, 12.5, photo.Location.Longitude, 1e-9)
+
+			if withSidecar {
+				require.Equal(t, "2024-04-28T12:26:40Z", photo.Date, "the sidecar date wins")
+			} else {
+				require.Equal(t, "2024-05-04T08:11:12Z", photo.Date)
+			}
+		})
+	}
+}
+
+func TestConvertVideoWithPosterImage(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	inputDir := filepath.Join(tmpDir, "input")
+	outputPath := filepath.Join(tmpDir, "output.zip")
+
+	require.NoError(t, os.MkdirAll(filepath.Join(inputDir, "Entries"), 0o750))
+	require.NoError(t, os.MkdirAll(filepath.Join(inputDir, "Resources"), 0o750))
+
+	entry := `<div class="pageHeader">7 May 2024</div>
+<div class="assetGrid"><div id="CLIP" class="gridItem assetType_video"></div></div>`
+
+	require.NoError(t, os.WriteFile(filepath.Join(inputDir, "Entries", "2024-05-07_Clip.html"), []byte(entry), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(inputDir, "Resources", "CLIP.jpg"), []byte("poster"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(inputDir, "Resources", "CLIP.mov"), minimalMovie(3), 0o600))
+
+	conv := converter.NewConverter(inputDir, "Journal")
+	require.NoError(t, conv.Convert(outputPath))
+
+	export := readExport(t, outputPath).Entries[0]
+	require.Empty(t, export.Photos, "the poster is not attached")
+	require.Len(t, export.Videos, 1)
+	require.Equal(t, "mov", export.Videos[0].Type)
+	require.Equal(t, 3, export.Videos[0].Duration)
+}
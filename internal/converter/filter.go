@@ -0,0 +1,97 @@
+package converter
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/kpod13/journal2day1/internal/models"
+)
+
+// Filter rule names, used as keys of the excluded entry counts in reports.
+const (
+	RuleSince     = "since"
+	RuleUntil     = "until"
+	RuleMatch     = "match"
+	RuleHasMedia  = "has-media"
+	RuleAssetType = "asset-type"
+)
+
+// mediaAssetTypes are the Apple Journal asset types converted to Day One media.
+var mediaAssetTypes = []string{"photo", "video", "audio"}
+
+// Filter selects the entries to convert. Zero fields do not filter.
+type Filter struct {
+	Since      time.Time      // keep entries dated at or after Since
+	Until      time.Time      // keep entries dated before Until
+	Match      *regexp.Regexp // keep entries whose title or body text matches
+	HasMedia   bool           // keep entries with at least one photo, video or audio recording
+	AssetTypes []string       // keep entries with an asset of one of these types
+}
+
+// exclusion returns the first rule that excludes the entry, or an empty
+// string when the entry passes the filter.
+func (f *Filter) exclusion(entry *models.AppleJournalEntry) string {
+	switch {
+	case !f.Since.IsZero() && entry.Date.Before(f.Since):
+		return RuleSince
+	case !f.Until.IsZero() && !entry.Date.Before(f.Until):
+		return RuleUntil
+	case f.Match != nil && !f.Match.MatchString(entryText(entry)):
+		return RuleMatch
+	case f.HasMedia && !hasAssetType(entry, mediaAssetTypes):
+		return RuleHasMedia
+	case len(f.AssetTypes) > 0 && !hasAssetType(entry, f.AssetTypes):
+		return RuleAssetType
+	}
+
+	return ""
+}
+
+// apply returns the entries that pass the filter and how many entries each
+// rule excluded.
+func (f *Filter) apply(entries []models.AppleJournalEntry) ([]models.AppleJournalEntry, map[string]int) {
+	kept := make([]models.AppleJournalEntry, 0, len(entries))
+	excluded := make(map[string]int)
+
+	for i := range entries {
+		if rule := f.exclusion(&entries[i]); rule != "" {
+			excluded[rule]++
+
+			continue
+		}
+
+		kept = append(kept, entries[i])
+	}
+
+	return kept, excluded
+}
+
+// entryText returns the title and plain body text of an entry, one block per
+// line, without Markdown escapes.
+func entryText(entry *models.AppleJournalEntry) string {
+	var text strings.Builder
+
+	text.WriteString(entry.Title)
+
+	for _, block := range entry.Blocks {
+		text.WriteString("\n")
+
+		for _, run := range block.Runs {
+			text.WriteString(run.Text)
+		}
+	}
+
+	return text.String()
+}
+
+func hasAssetType(entry *models.AppleJournalEntry, types []string) bool {
+	for _, asset := range entry.Assets {
+		if slices.Contains(types, asset.Type) {
+			return true
+		}
+	}
+
+	return false
+}
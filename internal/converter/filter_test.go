@@ -0,0 +1,137 @@
+package converter_test
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/kpod13/journal2day1/internal/converter"
+)
+
+func setupFilterTestData(t *testing.T, inputDir string) {
+	t.Helper()
+
+	entriesDir := filepath.Join(inputDir, "Entries")
+	resourcesDir := filepath.Join(inputDir, "Resources")
+
+	require.NoError(t, os.MkdirAll(entriesDir, 0o750))
+	require.NoError(t, os.MkdirAll(resourcesDir, 0o750))
+
+	entries := map[string]string{
+		"2024-03-01_Hike.html": `<div class="pageHeader">1 March 2024</div>
+<div class="assetGrid"><div id="HIKE-PHOTO" class="gridItem assetType_photo"></div></div>
+<div class='title'>Hike</div><div class='bodyText'>Climbed the *peak*</div>`,
+		"2024-06-10_Map.html": `<div class="pageHeader">10 June 2024</div>
+<div class="assetGrid"><div id="TRIP-MAP" class="gridItem assetType_genericMap"></div></div>
+<div class='title'>Trip</div><div class='bodyText'>Drove to the coast</div>`,
+		"2024-12-31_Notes.html": `<div class="pageHeader">31 December 2024</div>
+<div class='title'>Notes</div><div class='bodyText'>Year in review</div>`,
+		"2025-01-05_Later.html": `<div class="pageHeader">5 January 2025</div>
+<div class='title'>Later</div><div class='bodyText'>New year</div>`,
+	}
+
+	for name, content := range entries {
+		require.NoError(t, os.WriteFile(filepath.Join(entriesDir, name), []byte(content), 0o600))
+	}
+
+	require.NoError(t, os.WriteFile(filepath.Join(resourcesDir, "HIKE-PHOTO.jpg"), []byte("photo"), 0o600))
+}
+
+func TestConvertFilter(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		filter   converter.Filter
+		titles   []string
+		excluded map[string]int
+	}{
+		{
+			name:     "since",
+			filter:   converter.Filter{Since: time.Date(2024, 6, 10, 0, 0, 0, 0, time.UTC)},
+			titles:   []string{"Trip", "Notes", "Later"},
+			excluded: map[string]int{converter.RuleSince: 1},
+		},
+		{
+			name:     "until",
+			filter:   converter.Filter{Until: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)},
+			titles:   []string{"Hike", "Trip", "Notes"},
+			excluded: map[string]int{converter.RuleUntil: 1},
+		},
+		{
+			name:     "match on plain text",
+			filter:   converter.Filter{Match: regexp.MustCompile(`(?i)the \*peak\*|review`)},
+			titles:   []string{"Hike", "Notes"},
+			excluded: map[string]int{converter.RuleMatch: 2},
+		},
+		{
+			name:     "has media",
+			filter:   converter.Filter{HasMedia: true},
+			titles:   []string{"Hike"},
+			excluded: map[string]int{converter.RuleHasMedia: 3},
+		},
+		{
+			name:     "asset type",
+			filter:   converter.Filter{AssetTypes: []string{"map"}},
+			titles:   []string{"Trip"},
+			excluded: map[string]int{converter.RuleAssetType: 3},
+		},
+		{
+			name: "first failing rule counts",
+			filter: converter.Filter{
+				Since:    time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC),
+				HasMedia: true,
+			},
+			titles:   nil,
+			excluded: map[string]int{converter.RuleSince: 1, converter.RuleHasMedia: 3},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			tmpDir := t.TempDir()
+			inputDir := filepath.Join(tmpDir, "input")
+
+			setupFilterTestData(t, inputDir)
+
+			conv := converter.NewConverter(inputDir, "Journal")
+			conv.SetFilter(tc.filter)
+
+			outputPath := filepath.Join(tmpDir, "output.zip")
+			require.NoError(t, conv.Convert(outputPath))
+
+			var titles []string
+
+			for _, entry := range conv.Report().Entries {
+				titles = append(titles, entry.Title)
+			}
+
+			require.Equal(t, tc.titles, titles)
+			require.Len(t, readExport(t, outputPath).Entries, len(tc.titles))
+			require.Equal(t, tc.excluded, conv.Report().ExcludedEntries)
+			require.Equal(t, 4-len(tc.titles), conv.Report().Totals.ExcludedEntries)
+		})
+	}
+}
+
+func TestConvertFilterHasMediaKeepsAudio(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	inputDir := filepath.Join(tmpDir, "input")
+
+	setupAudioTestData(t, inputDir)
+
+	conv := converter.NewConverter(inputDir, "Journal")
+	conv.SetFilter(converter.Filter{HasMedia: true})
+	require.NoError(t, conv.Convert(filepath.Join(tmpDir, "output.zip")))
+
+	require.Len(t, conv.Report().Entries, 1, "recordings are media")
+	require.Empty(t, conv.Report().ExcludedEntries)
+}
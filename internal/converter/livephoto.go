@@ -0,0 +1,102 @@
+package converter
+
+import (
+	"path"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"github.com/kpod13/journal2day1/internal/models"
+)
+
+// Policies for converting Live Photos, which pair a still image with a short
+// motion video.
+const (
+	LivePhotoStill = "still" // attach the still image only
+	LivePhotoBoth  = "both"  // attach the still followed by the motion video
+	LivePhotoVideo = "video" // attach the motion video only
+)
+
+// ErrInvalidLivePhotoPolicy is returned for an unknown Live Photo policy.
+var ErrInvalidLivePhotoPolicy = errors.New("live photos must be still, both or video")
+
+// motionNamespace scopes the name-based identifiers of Live Photo motion
+// videos attached next to their still.
+var motionNamespace = uuid.NewSHA1(uuid.NameSpaceURL, []byte("https://github.com/kpod13/journal2day1/motion"))
+
+// SetLivePhotos sets how Live Photos are converted, LivePhotoStill by default.
+func (c *Converter) SetLivePhotos(policy string) error {
+	switch policy {
+	case LivePhotoStill, LivePhotoBoth, LivePhotoVideo:
+		c.livePhotos = policy
+
+		return nil
+	}
+
+	return errors.Wrapf(ErrInvalidLivePhotoPolicy, "%q", policy)
+}
+
+// assetFile is a resource file to attach for an asset.
+type assetFile struct {
+	path       string // within the export
+	ext        string
+	identifier string // Day One identifier of the attachment
+}
+
+// assetFiles returns the files to attach for a media asset. A Live Photo
+// yields its still, its motion video or both according to the Live Photo
+// policy; a lone motion video takes the asset's identifier. The path is empty
+// when the resource file is missing.
+func (c *Converter) assetFiles(asset models.AppleJournalAsset) []assetFile {
+	identifier := attachmentIdentifier(asset.ID)
+
+	if asset.Motion == nil {
+		return []assetFile{newAssetFile(c.parser.AssetFilePath(&asset), asset.Extension, identifier)}
+	}
+
+	var stillPath, motionPath string
+
+	if catalog, err := c.parser.Resources(); err == nil {
+		stillPath, motionPath = catalog.LivePhotoFiles(asset.ID)
+	}
+
+	still := newAssetFile(stillPath, asset.Extension, identifier)
+	motion := newAssetFile(motionPath, asset.Motion.Extension, identifier)
+
+	switch c.livePhotos {
+	case LivePhotoBoth:
+		motion.identifier = motionIdentifier(asset.ID)
+
+		return []assetFile{still, motion}
+	case LivePhotoVideo:
+		return []assetFile{motion}
+	default:
+		return []assetFile{still}
+	}
+}
+
+// newAssetFile returns a file to attach. The extension, which decides the
+// kind of attachment, comes from the resource file itself, as the grid item
+// of the asset may show a file of another kind; the given extension is the
+// fallback for a missing file.
+func newAssetFile(resourcePath, ext, identifier string) assetFile {
+	if resourceExt := strings.TrimPrefix(path.Ext(resourcePath), "."); resourceExt != "" {
+		ext = resourceExt
+	}
+
+	return assetFile{path: resourcePath, ext: ext, identifier: identifier}
+}
+
+// attachmentIdentifier returns the Day One identifier of an asset's
+// attachment: its ID in upper case without dashes.
+func attachmentIdentifier(assetID string) string {
+	return strings.ToUpper(strings.ReplaceAll(assetID, "-", ""))
+}
+
+// motionIdentifier returns the identifier of a Live Photo's motion video
+// attached next to its still, derived from the asset ID so that it is stable
+// across runs.
+func motionIdentifier(assetID string) string {
+	return attachmentIdentifier(uuid.NewSHA1(motionNamespace, []byte(assetID)).String())
+}
@@ -0,0 +1,222 @@
+package converter_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/kpod13/journal2day1/internal/converter"
+)
+
+func setupLivePhotoTestData(t *testing.T, inputDir string) {
+	t.Helper()
+
+	entriesDir := filepath.Join(inputDir, "Entries")
+	resourcesDir := filepath.Join(inputDir, "Resources")
+
+	require.NoError(t, os.MkdirAll(entriesDir, 0o750))
+	require.NoError(t, os.MkdirAll(resourcesDir, 0o750))
+
+	entry := `<div class="pageHeader">7 May 2024</div>
+<div class="assetGrid"><div id="LIVE-1" class="gridItem assetType_livePhoto"></div>
+<div id="PHOTO-2" class="gridItem assetType_photo"></div></div>
+<div class='bodyText'>At the waterfall</div>`
+
+	require.NoError(t, os.WriteFile(filepath.Join(entriesDir, "2024-05-07_Waterfall.html"), []byte(entry), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(resourcesDir, "LIVE-1.MOV"), minimalMovie(3), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(resourcesDir, "LIVE-1.heic"), []byte("still"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(resourcesDir, "PHOTO-2.jpg"), []byte("photo"), 0o600))
+}
+
+func TestConvertLivePhotos(t *testing.T) {
+	t.Parallel()
+
+	// MOTION stands for the identifier of a motion video attached next to its
+	// still.
+	tests := []struct {
+		policy     string
+		wantPhotos []string
+		wantVideos []string
+		wantOrder  []int // of the photos, then the videos
+		wantText   string
+		wantKinds  []string
+	}{
+		{
+			policy:     converter.LivePhotoStill,
+			wantPhotos: []string{"LIVE1", "PHOTO2"},
+			wantOrder:  []int{0, 1},
+			wantText:   "![](dayone-moment://LIVE1)\n![](dayone-moment://PHOTO2)\n\nAt the waterfall",
+			wantKinds:  []string{"photo", "photo"},
+		},
+		{
+			policy:     converter.LivePhotoBoth,
+			wantPhotos: []string{"LIVE1", "PHOTO2"},
+			wantVideos: []string{"MOTION"},
+			wantOrder:  []int{0, 2, 1},
+			wantText: "![](dayone-moment://LIVE1)\n![](dayone-moment:/video/MOTION)\n" +
+				"![](dayone-moment://PHOTO2)\n\nAt the waterfall",
+			wantKinds: []string{"photo", "video", "photo"},
+		},
+		{
+			policy:     converter.LivePhotoVideo,
+			wantPhotos: []string{"PHOTO2"},
+			wantVideos: []string{"LIVE1"},
+			wantOrder:  []int{1, 0},
+			wantText:   "![](dayone-moment:/video/LIVE1)\n![](dayone-moment://PHOTO2)\n\nAt the waterfall",
+			wantKinds:  []string{"video", "photo"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.policy, func(t *testing.T) {
+			t.Parallel()
+
+			tmpDir := t.TempDir()
+			inputDir := filepath.Join(tmpDir, "input")
+			outputPath := filepath.Join(tmpDir, "output.zip")
+
+			setupLivePhotoTestData(t, inputDir)
+
+			conv := converter.NewConverter(inputDir, "Journal")
+			require.NoError(t, conv.SetLivePhotos(tt.policy))
+			require.NoError(t, conv.Convert(outputPath))
+
+			entry := readExport(t, outputPath).Entries[0]
+			text := entry.Text
+
+			var (
+				photos, videos []string
+				order          []int
+			)
+
+			for _, photo := range entry.Photos {
+				photos = append(photos, photo.Identifier)
+				order = append(order, photo.OrderInEntry)
+			}
+
+			for _, video := range entry.Videos {
+				require.Equal(t, "mov", video.Type)
+				require.Equal(t, 3, video.Duration)
+
+				id := video.Identifier
+				if id != "LIVE1" {
+					require.Len(t, id, 32, "motion videos get an identifier of their own")
+					text = strings.ReplaceAll(text, id, "MOTION")
+					id = "MOTION"
+				}
+
+				videos = append(videos, id)
+				order = append(order, video.OrderInEntry)
+			}
+
+			require.Equal(t, tt.wantPhotos, photos)
+			require.Equal(t, tt.wantVideos, videos)
+			require.Equal(t, tt.wantOrder, order)
+			require.Equal(t, tt.wantText, text)
+			require.Len(t, zipMediaFiles(t, outputPath), len(photos)+len(videos))
+
+			var kinds []string
+
+			for _, asset := range conv.Report().Entries[0].Assets {
+				require.Equal(t, "photo", asset.Type)
+				require.Equal(t, converter.AssetCopied, asset.Status)
+				kinds = append(kinds, asset.Kind)
+			}
+
+			require.Equal(t, tt.wantKinds, kinds)
+		})
+	}
+}
+
+func TestConvertLivePhotoMotionIdentifierIsStable(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	inputDir := filepath.Join(tmpDir, "input")
+
+	setupLivePhotoTestData(t, inputDir)
+
+	var identifiers []string
+
+	for _, name := range []string{"first.zip", "second.zip"} {
+		conv := converter.NewConverter(inputDir, "Journal")
+		require.NoError(t, conv.SetLivePhotos(converter.LivePhotoBoth))
+		require.NoError(t, conv.Convert(filepath.Join(tmpDir, name)))
+
+		videos := readExport(t, filepath.Join(tmpDir, name)).Entries[0].Videos
+		require.Len(t, videos, 1)
+		identifiers = append(identifiers, videos[0].Identifier)
+	}
+
+	require.Equal(t, identifiers[0], identifiers[1])
+}
+
+func TestSetLivePhotosInvalid(t *testing.T) {
+	t.Parallel()
+
+	conv := converter.NewConverter(t.TempDir(), "Journal")
+	require.ErrorIs(t, conv.SetLivePhotos("motion"), converter.ErrInvalidLivePhotoPolicy)
+}
+
+func TestConvertPhotoAssetWithOnlyVideo(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	inputDir := filepath.Join(tmpDir, "input")
+	outputPath := filepath.Join(tmpDir, "output.zip")
+
+	require.NoError(t, os.MkdirAll(filepath.Join(inputDir, "Entries"), 0o750))
+	require.NoError(t, os.MkdirAll(filepath.Join(inputDir, "Resources"), 0o750))
+
+	// The grid item shows a still the export does not hold.
+	entry := `<div class="pageHeader">7 May 2024</div>
+<div class="assetGrid"><div id="CLIP" class="gridItem assetType_livePhoto"><img src="../Resources/CLIP.jpg"></div></div>`
+
+	require.NoError(t, os.WriteFile(filepath.Join(inputDir, "Entries", "2024-05-07_Clip.html"), []byte(entry), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(inputDir, "Resources", "CLIP.mov"), minimalMovie(3), 0o600))
+
+	conv := converter.NewConverter(inputDir, "Journal")
+	require.NoError(t, conv.Convert(outputPath))
+
+	export := readExport(t, outputPath).Entries[0]
+	require.Empty(t, export.Photos)
+	require.Len(t, export.Videos, 1)
+	require.Equal(t, "mov", export.Videos[0].Type)
+	require.Equal(t, 3, export.Videos[0].Duration)
+	require.Equal(t, "![](dayone-moment:/video/CLIP)", export.Text)
+
+	files := zipMediaFiles(t, outputPath)
+	require.Len(t, files, 1)
+	require.True(t, strings.HasPrefix(files[0], "videos/") && strings.HasSuffix(files[0], ".mov"), files[0])
+	require.Equal(t, "video", conv.Report().Entries[0].Assets[0].Kind)
+}
+
+func TestConvertPlainPhotoWithVideoNextToIt(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	inputDir := filepath.Join(tmpDir, "input")
+	outputPath := filepath.Join(tmpDir, "output.zip")
+
+	require.NoError(t, os.MkdirAll(filepath.Join(inputDir, "Entries"), 0o750))
+	require.NoError(t, os.MkdirAll(filepath.Join(inputDir, "Resources"), 0o750))
+
+	entry := `<div class="pageHeader">7 May 2024</div>
+<div class="assetGrid"><div id="SHOT" class="gridItem assetType_photo"></div></div>`
+
+	require.NoError(t, os.WriteFile(filepath.Join(inputDir, "Entries", "2024-05-07_Shot.html"), []byte(entry), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(inputDir, "Resources", "SHOT.MOV"), minimalMovie(3), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(inputDir, "Resources", "SHOT.jpg"), []byte("photo"), 0o600))
+
+	conv := converter.NewConverter(inputDir, "Journal")
+	require.NoError(t, conv.SetLivePhotos(converter.LivePhotoBoth))
+	require.NoError(t, conv.Convert(outputPath))
+
+	export := readExport(t, outputPath).Entries[0]
+	require.Len(t, export.Photos, 1, "only Live Photos carry a motion video")
+	require.Equal(t, "jpeg", export.Photos[0].Type)
+	require.Empty(t, export.Videos)
+}
@@ -0,0 +1,211 @@
+package converter
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/kpod13/journal2day1/internal/models"
+)
+
+// Rules for choosing the location of an entry from the locations of its
+// assets.
+const (
+	EntryLocationFirst = "first" // the location of the first asset that has one
+	EntryLocationLast  = "last"  // the location of the last asset that has one
+	EntryLocationNone  = "none"  // entries get no location
+)
+
+const (
+	assetTypeMap = "map"
+
+	// defaultRegionRadius is the radius in meters of the region around a map
+	// place whose sidecar does not give one.
+	defaultRegionRadius = 100
+)
+
+// ErrInvalidLocationRule is returned for an unknown entry location rule.
+var ErrInvalidLocationRule = errors.New("entry location must be first, last or none")
+
+// SetEntryLocation sets the rule choosing an entry's location from its
+// assets, EntryLocationFirst by default.
+func (c *Converter) SetEntryLocation(rule string) error {
+	switch rule {
+	case EntryLocationFirst, EntryLocationLast, EntryLocationNone:
+		c.entryLocation = rule
+
+		return nil
+	}
+
+	return errors.Wrapf(ErrInvalidLocationRule, "%q", rule)
+}
+
+// chooseEntryLocation returns the location of an entry according to the
+// entry location rule. Places the user attached as map assets come first;
+// without them each asset contributes the location from its sidecar or, for
+// photos, from EXIF.
+func (c *Converter) chooseEntryLocation(entry *models.AppleJournalEntry, photos []models.DayOnePhoto) *models.DayOneLocation {
+	if c.entryLocation == EntryLocationNone {
+		return nil
+	}
+
+	candidates := c.mapLocations(entry)
+	if len(candidates) > 0 {
+		return c.pickLocation(candidates)
+	}
+
+	for _, asset := range entry.Assets {
+		if location := sidecarLocation(c.resourceMeta(asset.ID)); location != nil {
+			candidates = append(candidates, location)
+
+			continue
+		}
+
+		if location := photoEXIFLocation(photos, asset.ID); location != nil {
+			candidates = append(candidates, location)
+		}
+	}
+
+	return c.pickLocation(candidates)
+}
+
+// pickLocation chooses among the candidate locations of an entry, in asset
+// order, by the entry location rule.
+func (c *Converter) pickLocation(candidates []*models.DayOneLocation) *models.DayOneLocation {
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	if c.entryLocation == EntryLocationLast {
+		return candidates[len(candidates)-1]
+	}
+
+	return candidates[0]
+}
+
+// mapLocations returns the locations of the entry's map assets. The sidecar
+// adds the locality, region and country to the parsed place.
+func (c *Converter) mapLocations(entry *models.AppleJournalEntry) []*models.DayOneLocation {
+	var locations []*models.DayOneLocation
+
+	for _, asset := range entry.Assets {
+		if asset.Type != assetTypeMap || asset.Place == nil {
+			continue
+		}
+
+		location := sidecarLocation(c.resourceMeta(asset.ID))
+		if location == nil {
+			location = &models.DayOneLocation{}
+		}
+
+		location.PlaceName = asset.Place.Label
+
+		if asset.Place.HasCoordinates() {
+			location.Latitude, location.Longitude = *asset.Place.Latitude, *asset.Place.Longitude
+
+			radius := asset.Place.Radius
+			if radius <= 0 {
+				radius = defaultRegionRadius
+			}
+
+			location.Region = &models.DayOneRegion{
+				Center: models.DayOneCenter{Latitude: location.Latitude, Longitude: location.Longitude},
+				Radius: radius,
+			}
+		}
+
+		locations = append(locations, location)
+	}
+
+	return locations
+}
+
+// withPlacesNote returns the entry with a closing paragraph listing the
+// places of its map assets when it has several, as Day One keeps only one
+// location per entry. Other entries are returned unchanged.
+func withPlacesNote(entry *models.AppleJournalEntry) *models.AppleJournalEntry {
+	var labels []string
+
+	for _, asset := range entry.Assets {
+		if asset.Type != assetTypeMap || asset.Place == nil {
+			continue
+		}
+
+		label := asset.Place.Label
+		if label == "" {
+			label = fmt.Sprintf("%.5f, %.5f", *asset.Place.Latitude, *asset.Place.Longitude)
+		}
+
+		labels = append(labels, label)
+	}
+
+	if len(labels) < 2 {
+		return entry
+	}
+
+	note := "Places: " + strings.Join(labels, "; ")
+	noted := *entry
+
+	if len(entry.Blocks) == 0 && entry.Body != "" {
+		noted.Body = entry.Body + "\n\n" + note
+
+		return &noted
+	}
+
+	noted.Blocks = append(slices.Clip(entry.Blocks), models.TextBlock{Runs: []models.TextRun{{Text: note}}})
+
+	return &noted
+}
+
+// sidecarLocation maps the location fields of an asset's sidecar to an entry
+// location, or returns nil when it has none.
+func sidecarLocation(meta *models.AppleJournalResourceMeta) *models.DayOneLocation {
+	if meta == nil || !meta.HasLocation() {
+		return nil
+	}
+
+	location := &models.DayOneLocation{
+		PlaceName:          meta.PlaceName,
+		LocalityName:       meta.Locality,
+		AdministrativeArea: meta.AdministrativeArea,
+		Country:            meta.Country,
+	}
+
+	if meta.HasCoordinates() {
+		location.Latitude, location.Longitude = *meta.Latitude, *meta.Longitude
+	}
+
+	return location
+}
+
+// sidecarPhotoLocation maps the coordinates of a photo's sidecar to a photo
+// location, or returns nil when it has none.
+func sidecarPhotoLocation(meta *models.AppleJournalResourceMeta) *models.DayOnePhotoLocation {
+	if meta == nil || !meta.HasCoordinates() {
+		return nil
+	}
+
+	return &models.DayOnePhotoLocation{
+		TimeZoneName: meta.TimeZone,
+		Latitude:     *meta.Latitude,
+		Longitude:    *meta.Longitude,
+	}
+}
+
+// photoEXIFLocation returns the coordinates a converted photo got from EXIF.
+func photoEXIFLocation(photos []models.DayOnePhoto, assetID string) *models.DayOneLocation {
+	identifier := strings.ToUpper(strings.ReplaceAll(assetID, "-", ""))
+
+	for i := range photos {
+		if photos[i].Identifier == identifier && photos[i].Location != nil {
+			return &models.DayOneLocation{
+				Latitude:  photos[i].Location.Latitude,
+				Longitude: photos[i].Location.Longitude,
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,229 @@
+package converter_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/kpod13/journal2day1/internal/converter"
+	"github.com/kpod13/journal2day1/internal/models"
+)
+
+func setupLocationTestData(t *testing.T, inputDir string) {
+	t.Helper()
+
+	entriesDir := filepath.Join(inputDir, "Entries")
+	resourcesDir := filepath.Join(inputDir, "Resources")
+
+	require.NoError(t, os.MkdirAll(entriesDir, 0o750))
+	require.NoError(t, os.MkdirAll(resourcesDir, 0o750))
+
+	entries := map[string]string{
+		"2024-05-04_Italy.html": `<div class="pageHeader">4 May 2024</div>
+<div class="assetGrid">
+<div id="NO-PLACE" class="gridItem assetType_photo"></div>
+<div id="ROME-PHOTO" class="gridItem assetType_photo"></div>
+<div id="FLORENCE-VIDEO" class="gridItem assetType_video"></div>
+</div>
+<div class='title'>Italy</div><div class='bodyText'>Rome, then Florence</div>`,
+		"2024-05-05_Home.html": `<div class="pageHeader">5 May 2024</div>
+<div class='title'>Home</div><div class='bodyText'>Back home</div>`,
+	}
+
+	for name, content := range entries {
+		require.NoError(t, os.WriteFile(filepath.Join(entriesDir, name), []byte(content), 0o600))
+	}
+
+	files := map[string]string{
+		"NO-PLACE.jpg":   "photo one",
+		"NO-PLACE.json":  `{"date": 736500000}`,
+		"ROME-PHOTO.jpg": "photo two",
+		"ROME-PHOTO.json": `{"date": 736500000, "placeName": "Colosseum", "locality": "Rome",
+"administrativeArea": "Lazio", "country": "Italy", "latitude": 41.89, "longitude": 12.49,
+"timeZone": "Europe/Rome"}`,
+		"FLORENCE-VIDEO.mov":  "video",
+		"FLORENCE-VIDEO.json": `{"placeName": "Ponte Vecchio", "locality": "Florence", "country": "Italy"}`,
+	}
+
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(resourcesDir, name), []byte(content), 0o600))
+	}
+}
+
+func TestConvertLocations(t *testing.T) {
+	t.Parallel()
+
+	rome := &models.DayOneLocation{
+		PlaceName:          "Colosseum",
+		LocalityName:       "Rome",
+		AdministrativeArea: "Lazio",
+		Country:            "Italy",
+		Latitude:           41.89,
+		Longitude:          12.49,
+	}
+	florence := &models.DayOneLocation{PlaceName: "Ponte Vecchio", LocalityName: "Florence", Country: "Italy"}
+
+	testCases := []struct {
+		rule string
+		want *models.DayOneLocation
+	}{
+		{rule: converter.EntryLocationFirst, want: rome},
+		{rule: converter.EntryLocationLast, want: florence},
+		{rule: converter.EntryLocationNone, want: nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.rule, func(t *testing.T) {
+			t.Parallel()
+
+			tmpDir := t.TempDir()
+			inputDir := filepath.Join(tmpDir, "input")
+			outputPath := filepath.Join(tmpDir, "output.zip")
+
+			setupLocationTestData(t, inputDir)
+
+			conv := converter.NewConverter(inputDir, "Journal")
+			require.NoError(t, conv.SetEntryLocation(tc.rule))
+			require.NoError(t, conv.Convert(outputPath))
+
+			entries := readExport(t, outputPath).Entries
+			require.Len(t, entries, 2)
+			require.Equal(t, tc.want, entries[0].Location)
+			require.Nil(t, entries[1].Location, "entries without assets have no location")
+
+			photos := entries[0].Photos
+			require.Len(t, photos, 2)
+			require.Nil(t, photos[0].Location)
+			require.Equal(t, &models.DayOnePhotoLocation{
+				TimeZoneName: "Europe/Rome",
+				Latitude:     41.89,
+				Longitude:    12.49,
+			}, photos[1].Location)
+		})
+	}
+}
+
+func TestSetEntryLocationInvalid(t *testing.T) {
+	t.Parallel()
+
+	conv := converter.NewConverter(t.TempDir(), "Journal")
+
+	require.ErrorIs(t, conv.SetEntryLocation("middle"), converter.ErrInvalidLocationRule)
+}
+
+func setupMapTestData(t *testing.T, inputDir string) {
+	t.Helper()
+
+	entriesDir := filepath.Join(inputDir, "Entries")
+	resourcesDir := filepath.Join(inputDir, "Resources")
+
+	require.NoError(t, os.MkdirAll(entriesDir, 0o750))
+	require.NoError(t, os.MkdirAll(resourcesDir, 0o750))
+
+	entry := `<div class="pageHeader">6 May 2024</div>
+<div class="assetGrid">
+<div id="ROME-PHOTO" class="gridItem assetType_photo"></div>
+<div id="COLOSSEUM-MAP" class="gridItem assetType_genericMap">
+<a href="https://maps.apple.com/?ll=41.8902,12.4922&amp;q=Colosseum"></a></div>
+<div id="BRIDGE-MAP" class="gridItem assetType_genericMap"></div>
+<div id="EMPTY-MAP" class="gridItem assetType_genericMap"></div>
+</div>
+<div class='title'>Sights</div><div class='bodyText'>Walked a lot</div>`
+
+	files := map[string]string{
+		"ROME-PHOTO.jpg":  "photo",
+		"ROME-PHOTO.json": `{"placeName": "Hotel", "latitude": 41.9, "longitude": 12.5}`,
+		"BRIDGE-MAP.json": `{"placeName": "Ponte Vecchio", "locality": "Florence", "country": "Italy",
+"latitude": 43.768, "longitude": 11.253, "radius": 250}`,
+	}
+
+	require.NoError(t, os.WriteFile(filepath.Join(entriesDir, "2024-05-06_Sights.html"), []byte(entry), 0o600))
+
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(resourcesDir, name), []byte(content), 0o600))
+	}
+}
+
+func TestConvertMapLocations(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		rule string
+		want *models.DayOneLocation
+	}{
+		{
+			rule: converter.EntryLocationFirst,
+			want: &models.DayOneLocation{
+				PlaceName: "Colosseum",
+				Latitude:  41.8902,
+				Longitude: 12.4922,
+				Region: &models.DayOneRegion{
+					Center: models.DayOneCenter{Latitude: 41.8902, Longitude: 12.4922},
+					Radius: 100,
+				},
+			},
+		},
+		{
+			rule: converter.EntryLocationLast,
+			want: &models.DayOneLocation{
+				PlaceName:    "Ponte Vecchio",
+				LocalityName: "Florence",
+				Country:      "Italy",
+				Latitude:     43.768,
+				Longitude:    11.253,
+				Region: &models.DayOneRegion{
+					Center: models.DayOneCenter{Latitude: 43.768, Longitude: 11.253},
+					Radius: 250,
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.rule, func(t *testing.T) {
+			t.Parallel()
+
+			tmpDir := t.TempDir()
+			inputDir := filepath.Join(tmpDir, "input")
+			outputPath := filepath.Join(tmpDir, "output.zip")
+
+			setupMapTestData(t, inputDir)
+
+			conv := converter.NewConverter(inputDir, "Journal")
+			require.NoError(t, conv.SetEntryLocation(tc.rule))
+			require.NoError(t, conv.Convert(outputPath))
+
+			entry := readExport(t, outputPath).Entries[0]
+			require.Equal(t, tc.want, entry.Location, "map assets win over the photo location")
+			require.True(t, strings.HasSuffix(entry.Text, "Walked a lot\nPlaces: Colosseum; Ponte Vecchio"), entry.Text)
+			require.Contains(t, entry.RichText, "Places: Colosseum; Ponte Vecchio")
+
+			report := conv.Report()
+			require.Equal(t, converter.AssetConverted, report.Entries[0].Assets[1].Status)
+			require.Equal(t, "location", report.Entries[0].Assets[1].Kind)
+			require.Equal(t, converter.AssetSkipped, report.Entries[0].Assets[3].Status)
+			require.Equal(t, 2, report.Totals.ConvertedAssets)
+		})
+	}
+}
+
+func TestConvertMapLocationsMediaAtEnd(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	inputDir := filepath.Join(tmpDir, "input")
+	outputPath := filepath.Join(tmpDir, "output.zip")
+
+	setupMapTestData(t, inputDir)
+
+	conv := converter.NewConverter(inputDir, "Journal")
+	conv.SetMediaAtEnd(true)
+	require.NoError(t, conv.Convert(outputPath))
+
+	entry := readExport(t, outputPath).Entries[0]
+	require.True(t, strings.HasSuffix(entry.Text,
+		"Places: Colosseum; Ponte Vecchio\n\n![](dayone-moment://ROMEPHOTO)"), entry.Text)
+}
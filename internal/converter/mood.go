@@ -0,0 +1,122 @@
+package converter
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/kpod13/journal2day1/internal/models"
+)
+
+const (
+	assetTypeStateOfMind = "stateOfMind"
+	moodTagPrefix        = "mood:"
+)
+
+// Parts of a State of Mind that can become tags.
+const (
+	MoodTagValence      = "valence"      // e.g. "mood:pleasant"
+	MoodTagLabels       = "labels"       // e.g. "mood:happy"
+	MoodTagAssociations = "associations" // e.g. "mood:family"
+)
+
+// ErrInvalidMoodTag is returned for an unknown part of a State of Mind.
+var ErrInvalidMoodTag = errors.New("mood tags must be valence, labels or associations")
+
+// SetMoodTags tags entries with the given parts of their State of Mind
+// assets, see MoodTagValence, MoodTagLabels and MoodTagAssociations.
+func (c *Converter) SetMoodTags(parts []string) error {
+	for _, part := range parts {
+		if part != MoodTagValence && part != MoodTagLabels && part != MoodTagAssociations {
+			return errors.Wrapf(ErrInvalidMoodTag, "%q", part)
+		}
+	}
+
+	c.moodTags = parts
+
+	return nil
+}
+
+// moodBlock renders a mood as a paragraph such as
+// "**Daily mood:** Slightly pleasant — happy, grateful (family, work)". A mood
+// with nothing to show renders no paragraph.
+func moodBlock(mood *models.StateOfMind) (models.TextBlock, bool) {
+	title := "Mood"
+
+	switch mood.Kind {
+	case "daily-mood":
+		title = "Daily mood"
+	case "momentary-emotion":
+		title = "Emotion"
+	}
+
+	var parts []string
+
+	if mood.Classification != "" {
+		parts = append(parts, capitalize(strings.ReplaceAll(mood.Classification, "-", " ")))
+	}
+
+	if labels := moodNames(mood.Labels); labels != "" {
+		parts = append(parts, labels)
+	}
+
+	text := strings.Join(parts, " — ")
+
+	if associations := moodNames(mood.Associations); associations != "" {
+		text = strings.TrimSpace(text + " (" + associations + ")")
+	}
+
+	if text == "" {
+		return models.TextBlock{}, false
+	}
+
+	return models.TextBlock{Runs: []models.TextRun{
+		{Text: title + ":", Style: models.TextStyle{Bold: true}},
+		{Text: " " + text},
+	}}, true
+}
+
+// moodNames joins the non-empty slugs as words, e.g. "happy, very sad".
+func moodNames(slugs []string) string {
+	var names []string
+
+	for _, slug := range slugs {
+		if slug = strings.TrimSpace(slug); slug != "" {
+			names = append(names, strings.ReplaceAll(slug, "-", " "))
+		}
+	}
+
+	return strings.Join(names, ", ")
+}
+
+// moodTags returns the "mood:" tags of an entry's State of Mind assets for
+// the given parts.
+func moodTags(entry *models.AppleJournalEntry, parts []string) []string {
+	var tags []string
+
+	for _, asset := range entry.Assets {
+		mood := asset.Mood
+		if mood == nil {
+			continue
+		}
+
+		if slices.Contains(parts, MoodTagValence) && mood.Classification != "" {
+			tags = append(tags, moodTagPrefix+mood.Classification)
+		}
+
+		if slices.Contains(parts, MoodTagLabels) {
+			for _, label := range mood.Labels {
+				tags = append(tags, moodTagPrefix+label)
+			}
+		}
+
+		if slices.Contains(parts, MoodTagAssociations) {
+			for _, association := range mood.Associations {
+				tags = append(tags, moodTagPrefix+association)
+			}
+		}
+	}
+
+	return tags
+}
@@ -0,0 +1,108 @@
+package converter_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/kpod13/journal2day1/internal/converter"
+)
+
+func setupMoodTestData(t *testing.T, inputDir string) {
+	t.Helper()
+
+	entriesDir := filepath.Join(inputDir, "Entries")
+	resourcesDir := filepath.Join(inputDir, "Resources")
+
+	require.NoError(t, os.MkdirAll(entriesDir, 0o750))
+	require.NoError(t, os.MkdirAll(resourcesDir, 0o750))
+
+	entry := `<div class="pageHeader">6 May 2024</div>
+<div class='title'>Evening</div>
+<div class="assetGrid"><div id="MOOD" class="gridItem assetType_stateOfMind"></div></div>
+<div class='bodyText'>Dinner with friends</div>
+<div class="assetGrid"><div id="FEELING" class="gridItem assetType_stateOfMind">Unpleasant</div></div>`
+
+	sidecar := `{"kind": "dailyMood", "valence": 0.6, "labels": ["happy", "grateful"], "associations": ["family", "friends"]}`
+
+	require.NoError(t, os.WriteFile(filepath.Join(entriesDir, "2024-05-06_Evening.html"), []byte(entry), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(resourcesDir, "MOOD.json"), []byte(sidecar), 0o600))
+}
+
+func TestConvertStateOfMind(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name  string
+		parts []string
+		tags  []string
+	}{
+		{name: "no tags"},
+		{name: "valence", parts: []string{converter.MoodTagValence}, tags: []string{"mood:pleasant", "mood:unpleasant"}},
+		{
+			name:  "labels and associations",
+			parts: []string{converter.MoodTagLabels, converter.MoodTagAssociations},
+			tags:  []string{"mood:happy", "mood:grateful", "mood:family", "mood:friends"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			tmpDir := t.TempDir()
+			inputDir := filepath.Join(tmpDir, "input")
+			outputPath := filepath.Join(tmpDir, "output.zip")
+
+			setupMoodTestData(t, inputDir)
+
+			conv := converter.NewConverter(inputDir, "Journal")
+			require.NoError(t, conv.SetMoodTags(tc.parts))
+			require.NoError(t, conv.Convert(outputPath))
+
+			entry := readExport(t, outputPath).Entries[0]
+			require.Equal(t, "# Evening\n\n"+
+				"**Daily mood:** Pleasant — happy, grateful (family, friends)\n"+
+				"Dinner with friends\n"+
+				"**Mood:** Unpleasant", entry.Text)
+			require.Equal(t, tc.tags, entry.Tags)
+
+			assets := conv.Report().Entries[0].Assets
+			require.Equal(t, converter.AssetConverted, assets[0].Status)
+			require.Equal(t, "mood", assets[0].Kind)
+		})
+	}
+}
+
+func TestSetMoodTagsInvalid(t *testing.T) {
+	t.Parallel()
+
+	conv := converter.NewConverter(t.TempDir(), "Journal")
+
+	require.ErrorIs(t, conv.SetMoodTags([]string{"valence", "colour"}), converter.ErrInvalidMoodTag)
+}
+
+func TestConvertStateOfMindNonASCIIClassification(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	inputDir := filepath.Join(tmpDir, "input")
+	outputPath := filepath.Join(tmpDir, "output.zip")
+
+	require.NoError(t, os.MkdirAll(filepath.Join(inputDir, "Entries"), 0o750))
+	require.NoError(t, os.MkdirAll(filepath.Join(inputDir, "Resources"), 0o750))
+
+	entry := `<div class="pageHeader">6 May 2024</div>
+<div class="assetGrid"><div id="MOOD" class="gridItem assetType_stateOfMind"></div></div>`
+
+	require.NoError(t, os.WriteFile(filepath.Join(inputDir, "Entries", "2024-05-06_Mood.html"), []byte(entry), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(inputDir, "Resources", "MOOD.json"),
+		[]byte(`{"kind": "dailyMood", "valenceClassification": "épanoui"}`), 0o600))
+
+	conv := converter.NewConverter(inputDir, "Journal")
+	require.NoError(t, conv.Convert(outputPath))
+
+	require.Equal(t, "**Daily mood:** Épanoui", readExport(t, outputPath).Entries[0].Text)
+}
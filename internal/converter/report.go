@@ -0,0 +1,182 @@
+package converter
+
+import (
+	"encoding/json"
+	"os"
+
+	"github.com/pkg/errors"
+
+	"github.com/kpod13/journal2day1/internal/parser"
+)
+
+// AssetStatus tells what happened to an asset during conversion. In a dry run
+// AssetCopied means the asset would be copied.
+type AssetStatus string
+
+// Asset statuses.
+const (
+	AssetCopied    AssetStatus = "copied"
+	AssetConverted AssetStatus = "converted" // turned into entry data, e.g. a map into the entry location
+	AssetSkipped   AssetStatus = "skipped"   // the export holds nothing to convert for the asset
+	AssetFailed    AssetStatus = "failed"
+)
+
+// Report describes the outcome of a conversion.
+type Report struct {
+	DryRun            bool                `json:"dryRun,omitempty"`
+	Archives          []string            `json:"archives,omitempty"` // file names, in writing order
+	Entries           []EntryReport       `json:"entries"`
+	FailedEntries     []FailedEntryReport `json:"failedEntries,omitempty"`
+	ExcludedEntries   map[string]int      `json:"excludedEntries,omitempty"` // by filter rule
+	OrphanedResources []string            `json:"orphanedResources,omitempty"`
+	Totals            ReportTotals        `json:"totals"`
+}
+
+// EntryReport describes a converted entry and its assets.
+type EntryReport struct {
+	Path    string        `json:"path"`
+	UUID    string        `json:"uuid"`
+	Title   string        `json:"title,omitempty"`
+	Date    string        `json:"date"` // ISO 8601 format
+	Undated bool          `json:"undated,omitempty"`
+	Journal string        `json:"journal"`
+	Tags    []string      `json:"tags,omitempty"`
+	Archive string        `json:"archive,omitempty"` // file name of the archive holding the entry
+	Assets  []AssetReport `json:"assets,omitempty"`
+}
+
+// AssetReport describes what happened to a single asset.
+type AssetReport struct {
+	ID     string      `json:"id"`
+	Type   string      `json:"type"`
+	Kind   string      `json:"kind,omitempty"` // Day One attachment kind of a copied asset
+	Status AssetStatus `json:"status"`
+	Reason string      `json:"reason,omitempty"`
+	File   string      `json:"file,omitempty"` // path within the output ZIP
+	Size   int64       `json:"size,omitempty"`
+}
+
+// FailedEntryReport describes an entry that could not be parsed.
+type FailedEntryReport struct {
+	Path   string `json:"path"`
+	Reason string `json:"reason"`
+}
+
+// ReportTotals sums up a report.
+type ReportTotals struct {
+	Entries           int    `json:"entries"`
+	FailedEntries     int    `json:"failedEntries"`
+	UnchangedEntries  int    `json:"unchangedEntries"` // left out as converted by an earlier run
+	ExcludedEntries   int    `json:"excludedEntries"`  // left out by the filter
+	UndatedEntries    int    `json:"undatedEntries"`
+	FirstDate         string `json:"firstDate,omitempty"` // of the dated entries
+	LastDate          string `json:"lastDate,omitempty"`
+	CopiedAssets      int    `json:"copiedAssets"`
+	ConvertedAssets   int    `json:"convertedAssets"`
+	SkippedAssets     int    `json:"skippedAssets"`
+	FailedAssets      int    `json:"failedAssets"`
+	Photos            int    `json:"photos"`
+	Videos            int    `json:"videos"`
+	Audios            int    `json:"audios"`
+	CopiedBytes       int64  `json:"copiedBytes"`
+	OrphanedResources int    `json:"orphanedResources"`
+}
+
+func newReport(entries []EntryReport, failures []parser.EntryError, orphans []string) *Report {
+	r := &Report{
+		Entries:           entries,
+		OrphanedResources: orphans,
+	}
+
+	for _, failure := range failures {
+		r.FailedEntries = append(r.FailedEntries, FailedEntryReport{
+			Path:   failure.Path,
+			Reason: failure.Err.Error(),
+		})
+	}
+
+	r.Totals = ReportTotals{
+		Entries:           len(entries),
+		FailedEntries:     len(failures),
+		OrphanedResources: len(orphans),
+	}
+
+	for i := range entries {
+		r.Totals.addEntry(&entries[i])
+	}
+
+	return r
+}
+
+func (t *ReportTotals) addEntry(entry *EntryReport) {
+	if entry.Undated {
+		t.UndatedEntries++
+	} else {
+		if t.FirstDate == "" || entry.Date < t.FirstDate {
+			t.FirstDate = entry.Date
+		}
+
+		if entry.Date > t.LastDate {
+			t.LastDate = entry.Date
+		}
+	}
+
+	for _, asset := range entry.Assets {
+		switch asset.Status {
+		case AssetCopied:
+			t.CopiedAssets++
+			t.CopiedBytes += asset.Size
+		case AssetConverted:
+			t.ConvertedAssets++
+		case AssetSkipped:
+			t.SkippedAssets++
+		case AssetFailed:
+			t.FailedAssets++
+		}
+
+		switch asset.Kind {
+		case kindPhoto:
+			t.Photos++
+		case kindVideo:
+			t.Videos++
+		case kindAudio:
+			t.Audios++
+		}
+	}
+}
+
+// Assets returns the reports of all assets with the given status, in entry
+// order, paired with the path of their entry.
+func (r *Report) Assets(status AssetStatus) []EntryAsset {
+	var assets []EntryAsset
+
+	for i := range r.Entries {
+		for _, asset := range r.Entries[i].Assets {
+			if asset.Status == status {
+				assets = append(assets, EntryAsset{Entry: r.Entries[i].Path, Asset: asset})
+			}
+		}
+	}
+
+	return assets
+}
+
+// EntryAsset pairs an asset report with the path of its entry.
+type EntryAsset struct {
+	Entry string
+	Asset AssetReport
+}
+
+// WriteJSON writes the report as indented JSON.
+func (r *Report) WriteJSON(path string) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal report")
+	}
+
+	if err := os.WriteFile(path, data, filePermission); err != nil {
+		return errors.Wrap(err, "failed to write report")
+	}
+
+	return nil
+}
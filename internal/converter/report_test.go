@@ -0,0 +1,159 @@
+package converter_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/kpod13/journal2day1/internal/converter"
+)
+
+func setupReportTestData(t *testing.T, inputDir string) {
+	t.Helper()
+
+	entriesDir := filepath.Join(inputDir, "Entries")
+	resourcesDir := filepath.Join(inputDir, "Resources")
+
+	require.NoError(t, os.MkdirAll(entriesDir, 0o750))
+	require.NoError(t, os.MkdirAll(resourcesDir, 0o750))
+
+	htmlContent := `<!DOCTYPE html>
+<html>
+<body>
+<div class="pageHeader">Monday, 15 December 2025</div>
+<div class="assetGrid">
+    <div id="PHOTO-UUID" class="gridItem assetType_photo"></div>
+    <div id="MAP-UUID" class="gridItem assetType_genericMap"></div>
+    <div id="MISSING-UUID" class="gridItem assetType_photo"></div>
+</div>
+<div class='title'>Report Entry</div>
+</body>
+</html>`
+
+	entryPath := filepath.Join(entriesDir, "2025-12-15_Report.html")
+	require.NoError(t, os.WriteFile(entryPath, []byte(htmlContent), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(resourcesDir, "PHOTO-UUID.jpg"), []byte("12345"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(resourcesDir, "UNUSED-UUID.jpg"), []byte("unused"), 0o600))
+}
+
+func TestConvertReport(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	inputDir := filepath.Join(tmpDir, "input")
+	outputPath := filepath.Join(tmpDir, "output.zip")
+
+	setupReportTestData(t, inputDir)
+
+	conv := converter.NewConverter(inputDir, "Journal")
+
+	require.Nil(t, conv.Report())
+	require.NoError(t, conv.Convert(outputPath))
+
+	report := conv.Report()
+	require.NotNil(t, report)
+	require.Len(t, report.Entries, 1)
+
+	entry := report.Entries[0]
+	require.Equal(t, filepath.Join(inputDir, "Entries", "2025-12-15_Report.html"), entry.Path)
+	require.Equal(t, "Report Entry", entry.Title)
+	require.Equal(t, "2025-12-15T00:00:00Z", entry.Date)
+	require.Equal(t, readExport(t, outputPath).Entries[0].UUID, entry.UUID)
+
+	require.Equal(t, []converter.AssetReport{
+		{
+			ID: "PHOTO-UUID", Type: "photo", Kind: "photo", Status: converter.AssetCopied,
+			File: "photos/827ccb0eea8a706c4c34a16891f84e7b.jpeg", Size: 5,
+		},
+		{ID: "MAP-UUID", Type: "map", Status: converter.AssetSkipped, Reason: "map asset has no place"},
+		{ID: "MISSING-UUID", Type: "photo", Status: converter.AssetFailed, Reason: "resource file not found"},
+	}, entry.Assets)
+
+	require.Equal(t, converter.ReportTotals{
+		Entries:           1,
+		FirstDate:         "2025-12-15T00:00:00Z",
+		LastDate:          "2025-12-15T00:00:00Z",
+		CopiedAssets:      1,
+		SkippedAssets:     1,
+		FailedAssets:      1,
+		Photos:            1,
+		CopiedBytes:       5,
+		OrphanedResources: 1,
+	}, report.Totals)
+	require.Equal(t, []string{"UNUSED-UUID"}, report.OrphanedResources)
+
+	failed := report.Assets(converter.AssetFailed)
+	require.Len(t, failed, 1)
+	require.Equal(t, entry.Path, failed[0].Entry)
+	require.Equal(t, "MISSING-UUID", failed[0].Asset.ID)
+}
+
+func TestReportWriteJSON(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	inputDir := filepath.Join(tmpDir, "input")
+
+	setupReportTestData(t, inputDir)
+
+	conv := converter.NewConverter(inputDir, "Journal")
+	require.NoError(t, conv.Convert(filepath.Join(tmpDir, "output.zip")))
+
+	reportPath := filepath.Join(tmpDir, "report.json")
+	require.NoError(t, conv.Report().WriteJSON(reportPath))
+
+	data, err := os.ReadFile(reportPath)
+	require.NoError(t, err)
+
+	var decoded converter.Report
+
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	require.Equal(t, *conv.Report(), decoded)
+	require.Contains(t, string(data), `"status": "skipped"`)
+	require.Contains(t, string(data), `"copiedBytes": 5`)
+}
+
+func TestConvertDryRun(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	inputDir := filepath.Join(tmpDir, "input")
+	outputPath := filepath.Join(tmpDir, "output.zip")
+
+	setupReportTestData(t, inputDir)
+
+	undated := `<!DOCTYPE html>
+<html>
+<body>
+<div class='title'>Undated</div>
+</body>
+</html>`
+	undatedPath := filepath.Join(inputDir, "Entries", "Undated.html")
+	require.NoError(t, os.WriteFile(undatedPath, []byte(undated), 0o600))
+
+	conv := converter.NewConverter(inputDir, "Journal")
+	conv.SetDryRun(true)
+
+	require.NoError(t, conv.Convert(outputPath))
+	require.NoFileExists(t, outputPath)
+
+	report := conv.Report()
+	require.True(t, report.DryRun)
+	require.Len(t, report.Entries, 2)
+	require.True(t, report.Entries[1].Undated)
+	require.Equal(t, filepath.Join(inputDir, "Entries", "Undated.html"), report.Entries[1].Path)
+
+	photo := report.Entries[0].Assets[0]
+	require.Equal(t, converter.AssetCopied, photo.Status)
+	require.Equal(t, int64(5), photo.Size)
+	require.Empty(t, photo.File)
+
+	require.Equal(t, 1, report.Totals.UndatedEntries)
+	require.Equal(t, "2025-12-15T00:00:00Z", report.Totals.FirstDate)
+	require.Equal(t, "2025-12-15T00:00:00Z", report.Totals.LastDate)
+	require.Equal(t, 1, report.Totals.Photos)
+	require.Equal(t, int64(5), report.Totals.CopiedBytes)
+}
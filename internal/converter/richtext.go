@@ -0,0 +1,138 @@
+package converter
+
+import (
+	"encoding/json"
+
+	"github.com/kpod13/journal2day1/internal/models"
+)
+
+const richTextVersion = 1
+
+// Attachment kinds used in media references and embedded objects.
+const (
+	kindPhoto    = "photo"
+	kindVideo    = "video"
+	kindAudio    = "audio"
+	kindLocation = "location" // a map asset converted into the entry location
+	kindActivity = "activity" // a motion activity converted into entry text
+	kindMood     = "mood"     // a State of Mind converted into entry text
+)
+
+// mediaRef records a converted attachment and where it belongs in the entry.
+type mediaRef struct {
+	kind       string // photo, video or audio
+	identifier string
+	position   int // index of the body block the attachment precedes
+}
+
+// buildRichText renders the entry as a Day One rich text document. Media
+// references must be ordered by position.
+func buildRichText(entry *models.AppleJournalEntry, refs []mediaRef) string {
+	doc := models.DayOneRichText{
+		Meta: models.DayOneRichTextMeta{Version: richTextVersion, SmallLinesRemoved: true},
+	}
+
+	if entry.Title != "" {
+		doc.Contents = append(doc.Contents, models.DayOneRichTextContent{
+			Text:       entry.Title + "\n",
+			Attributes: &models.DayOneRichTextAttributes{Line: &models.DayOneLineAttributes{Header: 1}},
+		})
+	}
+
+	next := 0
+
+	for i := range entry.Blocks {
+		doc.Contents, next = appendEmbeddedObjects(doc.Contents, refs, next, i)
+		doc.Contents = append(doc.Contents, blockContents(&entry.Blocks[i])...)
+	}
+
+	doc.Contents, _ = appendEmbeddedObjects(doc.Contents, refs, next, len(entry.Blocks))
+
+	data, err := json.Marshal(doc)
+	if err != nil {
+		// Rich text is optional, Day One falls back to the Markdown text.
+		return ""
+	}
+
+	return string(data)
+}
+
+// appendEmbeddedObjects adds the refs positioned before the given block,
+// starting at refs[next], and returns the index of the first ref left over.
+func appendEmbeddedObjects(
+	contents []models.DayOneRichTextContent,
+	refs []mediaRef,
+	next, position int,
+) ([]models.DayOneRichTextContent, int) {
+	var objects []models.DayOneEmbeddedObject
+
+	for ; next < len(refs) && refs[next].position <= position; next++ {
+		objects = append(objects, models.DayOneEmbeddedObject{
+			Type:       refs[next].kind,
+			Identifier: refs[next].identifier,
+		})
+	}
+
+	if len(objects) > 0 {
+		contents = append(contents, models.DayOneRichTextContent{EmbeddedObjects: objects})
+	}
+
+	return contents, next
+}
+
+func blockContents(block *models.TextBlock) []models.DayOneRichTextContent {
+	line := lineAttributes(block)
+	contents := make([]models.DayOneRichTextContent, 0, len(block.Runs))
+
+	for i, run := range block.Runs {
+		text := run.Text
+		if i == len(block.Runs)-1 {
+			text += "\n"
+		}
+
+		contents = append(contents, models.DayOneRichTextContent{
+			Text:       text,
+			Attributes: runAttributes(run.Style, line),
+		})
+	}
+
+	return contents
+}
+
+func lineAttributes(block *models.TextBlock) *models.DayOneLineAttributes {
+	line := &models.DayOneLineAttributes{Quote: block.Quote}
+
+	switch block.Kind {
+	case models.BlockHeading:
+		line.Header = block.Level
+	case models.BlockListItem:
+		line.ListStyle = "bulleted"
+		line.IndentLevel = block.Depth + 1
+
+		if block.Ordered {
+			line.ListStyle = "numbered"
+			line.ListIndex = block.Index
+		}
+	case models.BlockParagraph:
+		if !block.Quote {
+			return nil
+		}
+	}
+
+	return line
+}
+
+func runAttributes(style models.TextStyle, line *models.DayOneLineAttributes) *models.DayOneRichTextAttributes {
+	if style == (models.TextStyle{}) && line == nil {
+		return nil
+	}
+
+	return &models.DayOneRichTextAttributes{
+		Bold:          style.Bold,
+		Italic:        style.Italic,
+		Underline:     style.Underline,
+		Strikethrough: style.Strikethrough,
+		LinkURL:       style.Link,
+		Line:          line,
+	}
+}
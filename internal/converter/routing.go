@@ -0,0 +1,59 @@
+package converter
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/kpod13/journal2day1/internal/models"
+)
+
+// Route sends the entries that match all of its conditions to a journal.
+// Zero conditions always match.
+type Route struct {
+	Journal  string
+	Years    []int          // the entry is dated in one of these years
+	Hashtags []string       // the entry text has one of these hashtags, without "#"
+	Match    *regexp.Regexp // the entry title or body text matches
+	HasMedia bool           // the entry has at least one photo, video or audio recording
+}
+
+func (r *Route) matches(entry *models.AppleJournalEntry) bool {
+	switch {
+	case len(r.Years) > 0 && (entry.Date.IsZero() || !slices.Contains(r.Years, entry.Date.Year())):
+		return false
+	case len(r.Hashtags) > 0 && !hasHashtag(entry, r.Hashtags):
+		return false
+	case r.Match != nil && !r.Match.MatchString(entryText(entry)):
+		return false
+	case r.HasMedia && !hasAssetType(entry, mediaAssetTypes):
+		return false
+	}
+
+	return true
+}
+
+// journalFor returns the journal of the first route the entry matches, or
+// the default journal.
+func journalFor(routes []Route, entry *models.AppleJournalEntry, defaultJournal string) string {
+	for i := range routes {
+		if routes[i].matches(entry) {
+			return routes[i].Journal
+		}
+	}
+
+	return defaultJournal
+}
+
+// hasHashtag reports whether the entry has one of the hashtags, ignoring case.
+func hasHashtag(entry *models.AppleJournalEntry, hashtags []string) bool {
+	for _, tag := range entryHashtags(entry) {
+		if slices.ContainsFunc(hashtags, func(want string) bool {
+			return strings.EqualFold(strings.TrimPrefix(want, "#"), tag)
+		}) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,131 @@
+package converter_test
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/kpod13/journal2day1/internal/converter"
+	"github.com/kpod13/journal2day1/internal/models"
+)
+
+func setupRoutingTestData(t *testing.T, inputDir string) {
+	t.Helper()
+
+	entriesDir := filepath.Join(inputDir, "Entries")
+	resourcesDir := filepath.Join(inputDir, "Resources")
+
+	require.NoError(t, os.MkdirAll(entriesDir, 0o750))
+	require.NoError(t, os.MkdirAll(resourcesDir, 0o750))
+
+	entries := map[string]string{
+		"2023-05-01_Old.html": `<div class="pageHeader">1 May 2023</div>
+<div class='title'>Old</div><div class='bodyText'>Long ago</div>`,
+		"2024-03-01_Rome.html": `<div class="pageHeader">1 March 2024</div>
+<div class="assetGrid"><div id="ROME-PHOTO" class="gridItem assetType_photo"></div></div>
+<div class='title'>Rome</div><div class='bodyText'>Arrived today #Travel #food</div>`,
+		"2024-03-02_Standup.html": `<div class="pageHeader">2 March 2024</div>
+<div class='title'>Standup</div><div class='bodyText'>Team meeting notes</div>`,
+		"2024-03-03_Lunch.html": `<div class="pageHeader">3 March 2024</div>
+<div class="assetGrid"><div id="ROME-PHOTO" class="gridItem assetType_photo"></div></div>
+<div class='title'>Lunch</div><div class='bodyText'>Pasta again, issue #42</div>`,
+		"2024-03-04_Quiet.html": `<div class="pageHeader">4 March 2024</div>
+<div class='title'>Quiet</div><div class='bodyText'>Nothing happened</div>`,
+	}
+
+	for name, content := range entries {
+		require.NoError(t, os.WriteFile(filepath.Join(entriesDir, name), []byte(content), 0o600))
+	}
+
+	require.NoError(t, os.WriteFile(filepath.Join(resourcesDir, "ROME-PHOTO.jpg"), []byte("photo"), 0o600))
+}
+
+func TestConvertRoutes(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	inputDir := filepath.Join(tmpDir, "input")
+	outputPath := filepath.Join(tmpDir, "output.zip")
+
+	setupRoutingTestData(t, inputDir)
+
+	conv := converter.NewConverter(inputDir, "Personal")
+	conv.SetRoutes([]converter.Route{
+		{Journal: "Archive", Years: []int{2023}},
+		{Journal: "Travel", Hashtags: []string{"travel"}},
+		{Journal: "Work", Match: regexp.MustCompile(`(?i)\bmeeting\b`)},
+		{Journal: "Photos", HasMedia: true},
+	})
+	require.NoError(t, conv.Convert(outputPath))
+
+	journals := readJournals(t, outputPath)
+	require.Equal(t, map[string][]string{
+		"Archive.json":  {"Old"},
+		"Travel.json":   {"Rome"},
+		"Work.json":     {"Standup"},
+		"Photos.json":   {"Lunch"},
+		"Personal.json": {"Quiet"},
+	}, journals)
+
+	require.Len(t, zipMediaFiles(t, outputPath), 1, "shared photos are stored once")
+
+	for _, entry := range conv.Report().Entries {
+		require.Equal(t, entry.Title, journals[entry.Journal+".json"][0])
+	}
+}
+
+func TestConvertWithoutRoutes(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	inputDir := filepath.Join(tmpDir, "input")
+	outputPath := filepath.Join(tmpDir, "output.zip")
+
+	setupRoutingTestData(t, inputDir)
+
+	conv := converter.NewConverter(inputDir, "Personal")
+	require.NoError(t, conv.Convert(outputPath))
+
+	journals := readJournals(t, outputPath)
+	require.Len(t, journals, 1)
+	require.Len(t, journals["Personal.json"], 5)
+}
+
+// readJournals returns the entry titles of each journal JSON in the archive.
+func readJournals(t *testing.T, zipPath string) map[string][]string {
+	t.Helper()
+
+	zipReader, err := zip.OpenReader(zipPath)
+	require.NoError(t, err)
+
+	defer func() { _ = zipReader.Close() }() //nolint:errcheck // test cleanup
+
+	journals := make(map[string][]string)
+
+	for _, f := range zipReader.File {
+		if filepath.Ext(f.Name) != ".json" {
+			continue
+		}
+
+		rc, err := f.Open()
+		require.NoError(t, err)
+
+		var export models.DayOneExport
+
+		require.NoError(t, json.NewDecoder(rc).Decode(&export))
+		require.NoError(t, rc.Close())
+
+		for _, entry := range export.Entries {
+			title, _, _ := strings.Cut(entry.Text, "\n")
+			journals[f.Name] = append(journals[f.Name], strings.TrimPrefix(title, "# "))
+		}
+	}
+
+	return journals
+}
@@ -0,0 +1,179 @@
+package converter
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/kpod13/journal2day1/internal/models"
+)
+
+// Ways of grouping entries into archives.
+const (
+	SplitByYear  = "year"
+	SplitByMonth = "month"
+)
+
+const undatedGroup = "undated"
+
+// ErrInvalidSplit is returned for an unknown way of grouping entries.
+var ErrInvalidSplit = errors.New("split by must be year or month")
+
+// Split partitions the output into several archives. Each archive holds its
+// entries and only the media they reference. Zero fields do not split.
+type Split struct {
+	By      string // SplitByYear or SplitByMonth
+	MaxSize int64  // approximate upper bound of an archive's content in bytes
+}
+
+func (s Split) validate() error {
+	if s.By != "" && s.By != SplitByYear && s.By != SplitByMonth {
+		return errors.Wrapf(ErrInvalidSplit, "%q", s.By)
+	}
+
+	return nil
+}
+
+// archivePart is the set of entries written to one archive.
+type archivePart struct {
+	path    string
+	indexes []int // into the converted entries
+}
+
+// partition assigns the converted entries to archives named after the output
+// path, e.g. "import-2023.zip" or "import-2023-2.zip". Groups are ordered by
+// key, entries keep their order within a group.
+func (s Split) partition(outputPath string, export models.DayOneExport, reports []EntryReport) []archivePart {
+	if s == (Split{}) || len(reports) == 0 {
+		indexes := make([]int, len(reports))
+		for i := range indexes {
+			indexes[i] = i
+		}
+
+		return []archivePart{{path: outputPath, indexes: indexes}}
+	}
+
+	groups := make(map[string][]int)
+
+	for i := range reports {
+		key := s.groupKey(&reports[i])
+		groups[key] = append(groups[key], i)
+	}
+
+	keys := make([]string, 0, len(groups))
+	for key := range groups {
+		keys = append(keys, key)
+	}
+
+	slices.Sort(keys)
+
+	var parts []archivePart
+
+	for _, key := range keys {
+		chunks := s.chunk(groups[key], export, reports)
+
+		for n, chunk := range chunks {
+			suffix := key
+
+			if s.MaxSize > 0 {
+				suffix = strings.TrimPrefix(key+"-"+strconv.Itoa(n+1), "-")
+			}
+
+			parts = append(parts, archivePart{path: archivePath(outputPath, suffix), indexes: chunk})
+		}
+	}
+
+	return parts
+}
+
+func (s Split) groupKey(report *EntryReport) string {
+	switch {
+	case s.By == "":
+		return ""
+	case report.Undated:
+		return undatedGroup
+	case s.By == SplitByMonth:
+		return report.Date[:len("2006-01")]
+	default:
+		return report.Date[:len("2006")]
+	}
+}
+
+// chunk splits a group so the entries and unique media of each chunk stay
+// within MaxSize. An entry larger than MaxSize gets a chunk of its own.
+func (s Split) chunk(indexes []int, export models.DayOneExport, reports []EntryReport) [][]int {
+	if s.MaxSize <= 0 {
+		return [][]int{indexes}
+	}
+
+	var (
+		chunks  [][]int
+		current []int
+		size    int64
+		media   = make(map[string]bool)
+	)
+
+	for _, i := range indexes {
+		added := entrySize(&export.Entries[i], &reports[i], media)
+
+		if len(current) > 0 && size+added > s.MaxSize {
+			chunks = append(chunks, current)
+			current, size, media = nil, 0, make(map[string]bool)
+			added = entrySize(&export.Entries[i], &reports[i], media)
+		}
+
+		current = append(current, i)
+		size += added
+
+		for _, asset := range reports[i].Assets {
+			media[mediaKey(asset)] = true
+		}
+	}
+
+	return append(chunks, current)
+}
+
+// entrySize estimates how much an entry adds to an archive that already
+// holds the given media.
+func entrySize(entry *models.DayOneEntry, report *EntryReport, media map[string]bool) int64 {
+	var size int64
+
+	if data, err := json.Marshal(entry); err == nil {
+		size += int64(len(data))
+	}
+
+	for _, asset := range report.Assets {
+		if asset.Status == AssetCopied && !media[mediaKey(asset)] {
+			size += asset.Size
+		}
+	}
+
+	return size
+}
+
+// mediaKey identifies a media file. Dry runs do not copy files, so assets are
+// told apart by ID there.
+func mediaKey(asset AssetReport) string {
+	if asset.File != "" {
+		return asset.File
+	}
+
+	return asset.ID
+}
+
+// archivePath inserts a suffix before the extension of the output path.
+// Without an output path, as in a dry run, the suffix alone names the
+// archive.
+func archivePath(outputPath, suffix string) string {
+	if suffix == "" || outputPath == "" {
+		return suffix
+	}
+
+	ext := filepath.Ext(outputPath)
+
+	return strings.TrimSuffix(outputPath, ext) + "-" + suffix + ext
+}
@@ -0,0 +1,138 @@
+package converter_test
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/kpod13/journal2day1/internal/converter"
+)
+
+func TestConvertSplitByYear(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	inputDir := filepath.Join(tmpDir, "input")
+
+	setupFilterTestData(t, inputDir)
+
+	conv := converter.NewConverter(inputDir, "Journal")
+	require.NoError(t, conv.SetSplit(converter.Split{By: converter.SplitByYear}))
+	require.NoError(t, conv.Convert(filepath.Join(tmpDir, "output.zip")))
+
+	require.Equal(t, []string{"output-2024.zip", "output-2025.zip"}, conv.Report().Archives)
+	require.NoFileExists(t, filepath.Join(tmpDir, "output.zip"))
+
+	archive2024 := filepath.Join(tmpDir, "output-2024.zip")
+	require.Len(t, readExport(t, archive2024).Entries, 3)
+	require.Len(t, zipMediaFiles(t, archive2024), 1)
+
+	archive2025 := filepath.Join(tmpDir, "output-2025.zip")
+	require.Len(t, readExport(t, archive2025).Entries, 1)
+	require.Empty(t, zipMediaFiles(t, archive2025), "media of other years is left out")
+
+	for _, entry := range conv.Report().Entries {
+		require.Equal(t, "output-"+entry.Date[:4]+".zip", entry.Archive)
+	}
+}
+
+func TestConvertSplitByMonthDryRun(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	inputDir := filepath.Join(tmpDir, "input")
+
+	setupFilterTestData(t, inputDir)
+
+	conv := converter.NewConverter(inputDir, "Journal")
+	conv.SetDryRun(true)
+	require.NoError(t, conv.SetSplit(converter.Split{By: converter.SplitByMonth}))
+	require.NoError(t, conv.Convert(filepath.Join(tmpDir, "import.zip")))
+
+	require.Equal(t, []string{
+		"import-2024-03.zip", "import-2024-06.zip", "import-2024-12.zip", "import-2025-01.zip",
+	}, conv.Report().Archives)
+	require.NoFileExists(t, filepath.Join(tmpDir, "import-2024-03.zip"))
+}
+
+func TestConvertSplitByYearDryRunWithoutOutput(t *testing.T) {
+	t.Parallel()
+
+	inputDir := filepath.Join(t.TempDir(), "input")
+
+	setupFilterTestData(t, inputDir)
+
+	conv := converter.NewConverter(inputDir, "Journal")
+	conv.SetDryRun(true)
+	require.NoError(t, conv.SetSplit(converter.Split{By: converter.SplitByYear}))
+	require.NoError(t, conv.Convert(""))
+
+	report := conv.Report()
+	require.Equal(t, []string{"2024", "2025"}, report.Archives)
+	require.Equal(t, "2024", report.Entries[0].Archive)
+}
+
+func TestConvertSplitBySize(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	inputDir := filepath.Join(tmpDir, "input")
+	entriesDir := filepath.Join(inputDir, "Entries")
+	resourcesDir := filepath.Join(inputDir, "Resources")
+
+	require.NoError(t, os.MkdirAll(entriesDir, 0o750))
+	require.NoError(t, os.MkdirAll(resourcesDir, 0o750))
+
+	for _, name := range []string{"A", "B", "C"} {
+		html := `<div class="pageHeader">1 March 2024</div>
+<div class="assetGrid"><div id="PHOTO-` + name + `" class="gridItem assetType_photo"></div></div>
+<div class='title'>` + name + `</div>`
+
+		require.NoError(t, os.WriteFile(filepath.Join(entriesDir, "2024-03-01_"+name+".html"), []byte(html), 0o600))
+		require.NoError(t, os.WriteFile(
+			filepath.Join(resourcesDir, "PHOTO-"+name+".jpg"), []byte(strings.Repeat(name, 4000)), 0o600))
+	}
+
+	conv := converter.NewConverter(inputDir, "Journal")
+	require.NoError(t, conv.SetSplit(converter.Split{MaxSize: 6000}))
+	require.NoError(t, conv.Convert(filepath.Join(tmpDir, "output.zip")))
+
+	require.Equal(t, []string{"output-1.zip", "output-2.zip", "output-3.zip"}, conv.Report().Archives)
+
+	for _, archive := range conv.Report().Archives {
+		path := filepath.Join(tmpDir, archive)
+		require.Len(t, readExport(t, path).Entries, 1)
+		require.Len(t, zipMediaFiles(t, path), 1)
+	}
+}
+
+func TestSetSplitInvalid(t *testing.T) {
+	t.Parallel()
+
+	conv := converter.NewConverter(t.TempDir(), "Journal")
+
+	require.ErrorIs(t, conv.SetSplit(converter.Split{By: "week"}), converter.ErrInvalidSplit)
+}
+
+func zipMediaFiles(t *testing.T, zipPath string) []string {
+	t.Helper()
+
+	zipReader, err := zip.OpenReader(zipPath)
+	require.NoError(t, err)
+
+	defer func() { _ = zipReader.Close() }() //nolint:errcheck // test cleanup
+
+	var files []string
+
+	for _, f := range zipReader.File {
+		if !strings.HasSuffix(f.Name, ".json") {
+			files = append(files, f.Name)
+		}
+	}
+
+	return files
+}
@@ -0,0 +1,129 @@
+package converter
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/kpod13/journal2day1/internal/models"
+)
+
+const stateVersion = 1
+
+// Sentinel errors for incremental conversion.
+var (
+	ErrNothingNew         = errors.New("no new or changed entries since the last run")
+	ErrUnsupportedVersion = errors.New("unsupported state file version")
+)
+
+// State remembers which source entries earlier runs converted, so later runs
+// can convert only new or changed ones.
+type State struct {
+	Version int                   `json:"version"`
+	Entries map[string]StateEntry `json:"entries"` // keyed by entry file name
+}
+
+// StateEntry records a converted source entry.
+type StateEntry struct {
+	ContentHash string `json:"contentHash"`
+	UUID        string `json:"uuid"`
+	ConvertedAt string `json:"convertedAt"` // ISO 8601 format
+}
+
+// LoadState reads a state file. A missing file yields an empty state.
+func LoadState(path string) (*State, error) {
+	data, err := os.ReadFile(filepath.Clean(path))
+	if errors.Is(err, os.ErrNotExist) {
+		return &State{Version: stateVersion, Entries: make(map[string]StateEntry)}, nil
+	}
+
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read state")
+	}
+
+	var state State
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, errors.Wrap(err, "failed to parse state")
+	}
+
+	if state.Version != stateVersion {
+		return nil, errors.Wrapf(ErrUnsupportedVersion, "%d", state.Version)
+	}
+
+	if state.Entries == nil {
+		state.Entries = make(map[string]StateEntry)
+	}
+
+	return &state, nil
+}
+
+// Save writes the state atomically: a crash leaves either the old or the new
+// file, never a partial one.
+func (s *State) Save(path string) (err error) {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal state")
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".state-*")
+	if err != nil {
+		return errors.Wrap(err, "failed to create state file")
+	}
+
+	defer func() {
+		if err != nil {
+			_ = os.Remove(tmp.Name()) //nolint:errcheck // the write error is more relevant
+		}
+	}()
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close() //nolint:errcheck // the write error is more relevant
+
+		return errors.Wrap(err, "failed to write state")
+	}
+
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close() //nolint:errcheck // the sync error is more relevant
+
+		return errors.Wrap(err, "failed to sync state")
+	}
+
+	if err := tmp.Close(); err != nil {
+		return errors.Wrap(err, "failed to close state file")
+	}
+
+	return errors.Wrap(os.Rename(tmp.Name(), path), "failed to replace state file")
+}
+
+// IsConverted reports whether the entry was converted before and has not
+// changed since.
+func (s *State) IsConverted(entry *models.AppleJournalEntry) bool {
+	recorded, ok := s.Entries[filepath.Base(entry.FilePath)]
+
+	return ok && recorded.ContentHash == entry.ContentHash
+}
+
+// uuids returns the Day One UUIDs of the recorded entries by file name.
+func (s *State) uuids() map[string]string {
+	uuids := make(map[string]string, len(s.Entries))
+
+	for name, entry := range s.Entries {
+		uuids[name] = entry.UUID
+	}
+
+	return uuids
+}
+
+// record marks the entries of a report as converted.
+func (s *State) record(entries []models.AppleJournalEntry, reports []EntryReport, now time.Time) {
+	for i := range entries {
+		s.Entries[filepath.Base(entries[i].FilePath)] = StateEntry{
+			ContentHash: entries[i].ContentHash,
+			UUID:        reports[i].UUID,
+			ConvertedAt: now.UTC().Format(iso8601Format),
+		}
+	}
+}
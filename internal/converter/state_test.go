@@ -0,0 +1,151 @@
+package converter_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/kpod13/journal2day1/internal/converter"
+	"github.com/kpod13/journal2day1/internal/models"
+)
+
+func TestLoadStateMissingFile(t *testing.T) {
+	t.Parallel()
+
+	state, err := converter.LoadState(filepath.Join(t.TempDir(), "state.json"))
+
+	require.NoError(t, err)
+	require.Equal(t, 1, state.Version)
+	require.Empty(t, state.Entries)
+}
+
+func TestStateSaveAndLoad(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	statePath := filepath.Join(tmpDir, "state.json")
+
+	state := &converter.State{
+		Version: 1,
+		Entries: map[string]converter.StateEntry{
+			"2025-12-15_Entry.html": {ContentHash: "abc", UUID: "UUID1", ConvertedAt: "2025-12-16T10:00:00Z"},
+		},
+	}
+
+	require.NoError(t, state.Save(statePath))
+
+	loaded, err := converter.LoadState(statePath)
+	require.NoError(t, err)
+	require.Equal(t, state, loaded)
+
+	files, err := os.ReadDir(tmpDir)
+	require.NoError(t, err)
+	require.Len(t, files, 1, "no temporary files may be left behind")
+
+	entry := &models.AppleJournalEntry{FilePath: "/export/Entries/2025-12-15_Entry.html", ContentHash: "abc"}
+	require.True(t, loaded.IsConverted(entry))
+
+	entry.ContentHash = "changed"
+	require.False(t, loaded.IsConverted(entry))
+}
+
+func TestLoadStateErrors(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name    string
+		content string
+		target  error
+	}{
+		{name: "invalid JSON", content: "{"},
+		{name: "unknown version", content: `{"version": 7, "entries": {}}`, target: converter.ErrUnsupportedVersion},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			statePath := filepath.Join(t.TempDir(), "state.json")
+			require.NoError(t, os.WriteFile(statePath, []byte(tc.content), 0o600))
+
+			_, err := converter.LoadState(statePath)
+			require.Error(t, err)
+
+			if tc.target != nil {
+				require.ErrorIs(t, err, tc.target)
+			}
+		})
+	}
+}
+
+func TestConvertIncremental(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	inputDir := filepath.Join(tmpDir, "input")
+	statePath := filepath.Join(tmpDir, "state.json")
+
+	setupMultipleEntriesData(t, inputDir)
+
+	convert := func(name string) (*converter.Converter, error) {
+		conv := converter.NewConverter(inputDir, "Journal")
+		conv.SetStatePath(statePath)
+
+		return conv, conv.Convert(filepath.Join(tmpDir, name+".zip"))
+	}
+
+	first, err := convert("first")
+	require.NoError(t, err)
+	require.Len(t, readExport(t, filepath.Join(tmpDir, "first.zip")).Entries, 3)
+	require.Equal(t, 0, first.Report().Totals.UnchangedEntries)
+
+	state, err := converter.LoadState(statePath)
+	require.NoError(t, err)
+	require.Len(t, state.Entries, 3)
+	require.Equal(t, first.Report().Entries[0].UUID, state.Entries["2025-12-15_Entry_1.html"].UUID)
+
+	editedUUID := state.Entries["2025-12-15_Entry_2.html"].UUID
+	require.NotEmpty(t, editedUUID)
+
+	_, err = convert("second")
+	require.ErrorIs(t, err, converter.ErrNothingNew)
+	require.NoFileExists(t, filepath.Join(tmpDir, "second.zip"))
+
+	entriesDir := filepath.Join(inputDir, "Entries")
+	require.NoError(t, os.WriteFile(filepath.Join(entriesDir, "2025-12-15_Entry_2.html"),
+		[]byte("<div class='title'>Edited</div>"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(entriesDir, "2025-12-16_New.html"),
+		[]byte("<div class='title'>New</div>"), 0o600))
+
+	third, err := convert("third")
+	require.NoError(t, err)
+	require.Equal(t, 2, third.Report().Totals.UnchangedEntries)
+
+	export := readExport(t, filepath.Join(tmpDir, "third.zip"))
+	require.Len(t, export.Entries, 2)
+	require.Contains(t, export.Entries[0].Text, "Edited")
+	require.Equal(t, editedUUID, export.Entries[0].UUID, "edited entries keep their UUID")
+	require.Contains(t, export.Entries[1].Text, "New")
+
+	state, err = converter.LoadState(statePath)
+	require.NoError(t, err)
+	require.Len(t, state.Entries, 4)
+}
+
+func TestConvertIncrementalKeepsStateOnFailure(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	inputDir := filepath.Join(tmpDir, "input")
+	statePath := filepath.Join(tmpDir, "state.json")
+
+	setupMultipleEntriesData(t, inputDir)
+
+	conv := converter.NewConverter(inputDir, "Journal")
+	conv.SetStatePath(statePath)
+
+	require.Error(t, conv.Convert(filepath.Join(tmpDir, "missing", "output.zip")))
+	require.NoFileExists(t, statePath)
+}
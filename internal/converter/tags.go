@@ -0,0 +1,196 @@
+package converter
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/kpod13/journal2day1/internal/models"
+)
+
+// hashtagPattern matches a hashtag at the start of the text or after a space.
+// Hashtags need a letter, so "#42" is not one.
+var hashtagPattern = regexp.MustCompile(`(?:^|\s)#([\p{L}\p{N}_-]*[\p{L}_-][\p{L}\p{N}_-]*)`)
+
+// TagRule tags the entries that match all of its conditions. Zero conditions
+// always match.
+type TagRule struct {
+	Tag        string
+	Keywords   []string       // the entry title or text contains one of these, ignoring case
+	Match      *regexp.Regexp // the entry title or body text matches
+	AssetTypes []string       // the entry has an asset of one of these types
+	Since      time.Time      // the entry is dated at or after Since
+	Until      time.Time      // the entry is dated before Until
+}
+
+func (r *TagRule) matches(entry *models.AppleJournalEntry) bool {
+	dated := !entry.Date.IsZero()
+
+	switch {
+	case len(r.Keywords) > 0 && !containsKeyword(entryText(entry), r.Keywords):
+		return false
+	case r.Match != nil && !r.Match.MatchString(entryText(entry)):
+		return false
+	case len(r.AssetTypes) > 0 && !hasAssetType(entry, r.AssetTypes):
+		return false
+	case !r.Since.IsZero() && (!dated || entry.Date.Before(r.Since)):
+		return false
+	case !r.Until.IsZero() && (!dated || !entry.Date.Before(r.Until)):
+		return false
+	}
+
+	return true
+}
+
+// entryTags returns the Day One tags of an entry: the fixed tags, its
+// hashtags, the tags of matching rules and, when enabled, its activity and
+// mood tags, without duplicates.
+func (c *Converter) entryTags(entry *models.AppleJournalEntry) []string {
+	var tags []string
+
+	add := func(tag string) {
+		if tag != "" && !slices.ContainsFunc(tags, func(t string) bool { return strings.EqualFold(t, tag) }) {
+			tags = append(tags, tag)
+		}
+	}
+
+	for _, tag := range c.tags {
+		add(tag)
+	}
+
+	for _, tag := range entryHashtags(entry) {
+		add(tag)
+	}
+
+	for i := range c.tagRules {
+		if c.tagRules[i].matches(entry) {
+			add(c.tagRules[i].Tag)
+		}
+	}
+
+	if c.activityTags {
+		for _, tag := range activityTags(entry) {
+			add(tag)
+		}
+	}
+
+	for _, tag := range moodTags(entry, c.moodTags) {
+		add(tag)
+	}
+
+	return tags
+}
+
+// entryHashtags returns the hashtags of an entry's title and text in order of
+// appearance, without "#" and duplicates.
+func entryHashtags(entry *models.AppleJournalEntry) []string {
+	var tags []string
+
+	for _, match := range hashtagPattern.FindAllStringSubmatch(entryText(entry), -1) {
+		if !slices.ContainsFunc(tags, func(tag string) bool { return strings.EqualFold(tag, match[1]) }) {
+			tags = append(tags, match[1])
+		}
+	}
+
+	return tags
+}
+
+func containsKeyword(text string, keywords []string) bool {
+	text = strings.ToLower(text)
+
+	for _, keyword := range keywords {
+		if keyword != "" && strings.Contains(text, strings.ToLower(keyword)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// stripHashtags returns a copy of the entry without hashtags in its title and
+// text. Hashtags are found in the entry text, so a hashtag split across
+// differently styled runs is removed whole. Blocks left empty are dropped and
+// the positions of the assets after them are shifted.
+func stripHashtags(entry *models.AppleJournalEntry) *models.AppleJournalEntry {
+	spans := hashtagPattern.FindAllStringIndex(entryText(entry), -1)
+
+	stripped := *entry
+	stripped.Title = strings.TrimSpace(cutSpans(entry.Title, 0, spans))
+	stripped.Body = removeHashtags(entry.Body)
+	stripped.Blocks = make([]models.TextBlock, 0, len(entry.Blocks))
+	stripped.Assets = slices.Clone(entry.Assets)
+
+	var dropped []int
+
+	// Offsets follow entryText: the title, then each block after a newline.
+	offset := len(entry.Title)
+
+	for i, block := range entry.Blocks {
+		block.Runs = slices.Clone(block.Runs)
+		offset++
+
+		for j := range block.Runs {
+			text := block.Runs[j].Text
+			block.Runs[j].Text = cutSpans(text, offset, spans)
+			offset += len(text)
+		}
+
+		if n := len(block.Runs); n > 0 {
+			block.Runs[0].Text = strings.TrimLeft(block.Runs[0].Text, " ")
+			block.Runs[n-1].Text = strings.TrimRight(block.Runs[n-1].Text, " ")
+		}
+
+		if blockText(block) == "" && blockText(entry.Blocks[i]) != "" {
+			dropped = append(dropped, i)
+
+			continue
+		}
+
+		stripped.Blocks = append(stripped.Blocks, block)
+	}
+
+	for k, asset := range entry.Assets {
+		for _, i := range dropped {
+			if i < asset.Position {
+				stripped.Assets[k].Position--
+			}
+		}
+	}
+
+	return &stripped
+}
+
+// cutSpans removes from text, which starts at offset in the text the spans
+// index, the parts the spans cover.
+func cutSpans(text string, offset int, spans [][]int) string {
+	var kept strings.Builder
+
+	start := 0
+
+	for _, span := range spans {
+		from := min(max(span[0]-offset, start), len(text))
+		to := min(max(span[1]-offset, start), len(text))
+
+		kept.WriteString(text[start:from])
+		start = to
+	}
+
+	kept.WriteString(text[start:])
+
+	return kept.String()
+}
+
+func removeHashtags(text string) string {
+	return strings.TrimSpace(hashtagPattern.ReplaceAllString(text, ""))
+}
+
+func blockText(block models.TextBlock) string {
+	var text strings.Builder
+
+	for _, run := range block.Runs {
+		text.WriteString(run.Text)
+	}
+
+	return strings.TrimSpace(text.String())
+}
@@ -0,0 +1,112 @@
+package converter_test
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/kpod13/journal2day1/internal/converter"
+)
+
+func setupTagsTestData(t *testing.T, inputDir string) {
+	t.Helper()
+
+	entriesDir := filepath.Join(inputDir, "Entries")
+	resourcesDir := filepath.Join(inputDir, "Resources")
+
+	require.NoError(t, os.MkdirAll(entriesDir, 0o750))
+	require.NoError(t, os.MkdirAll(resourcesDir, 0o750))
+
+	entries := map[string]string{
+		"2024-03-01_Rome.html": `<div class="pageHeader">1 March 2024</div>
+<div class='title'>Rome</div>
+<div class='bodyText'>Arrived today #Travel #food</div>
+<div class='bodyText'>#italy</div>
+<div class="assetGrid"><div id="ROME-PHOTO" class="gridItem assetType_photo"></div></div>
+<div class='bodyText'>Pasta again, issue #42 and #travel</div>`,
+		"2024-06-02_Standup.html": `<div class="pageHeader">2 June 2024</div>
+<div class='title'>Standup</div><div class='bodyText'>Team Meeting notes</div>`,
+	}
+
+	for name, content := range entries {
+		require.NoError(t, os.WriteFile(filepath.Join(entriesDir, name), []byte(content), 0o600))
+	}
+
+	require.NoError(t, os.WriteFile(filepath.Join(resourcesDir, "ROME-PHOTO.jpg"), []byte("photo"), 0o600))
+}
+
+func TestConvertTags(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	inputDir := filepath.Join(tmpDir, "input")
+	outputPath := filepath.Join(tmpDir, "output.zip")
+
+	setupTagsTestData(t, inputDir)
+
+	conv := converter.NewConverter(inputDir, "Journal")
+	conv.SetTags([]string{"apple-journal"})
+	conv.SetTagRules([]converter.TagRule{
+		{Tag: "work", Keywords: []string{"meeting", "standup"}},
+		{Tag: "photos", AssetTypes: []string{"photo"}},
+		{Tag: "spring", Since: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), Until: time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)},
+		{Tag: "pasta", Match: regexp.MustCompile(`(?i)pasta`)},
+		{Tag: "travel", Keywords: []string{"rome"}},
+	})
+	require.NoError(t, conv.Convert(outputPath))
+
+	export := readExport(t, outputPath)
+	require.Len(t, export.Entries, 2)
+
+	rome, standup := export.Entries[0], export.Entries[1]
+	require.Equal(t, []string{"apple-journal", "Travel", "food", "italy", "photos", "spring", "pasta"}, rome.Tags)
+	require.Equal(t, []string{"apple-journal", "work"}, standup.Tags)
+	require.Contains(t, rome.Text, "Arrived today #Travel #food", "hashtags are kept by default")
+	require.Equal(t, rome.Tags, conv.Report().Entries[0].Tags)
+}
+
+func TestConvertStripHashtags(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	inputDir := filepath.Join(tmpDir, "input")
+	outputPath := filepath.Join(tmpDir, "output.zip")
+
+	setupTagsTestData(t, inputDir)
+
+	conv := converter.NewConverter(inputDir, "Journal")
+	conv.SetStripHashtags(true)
+	require.NoError(t, conv.Convert(outputPath))
+
+	rome := readExport(t, outputPath).Entries[0]
+	require.Equal(t, []string{"Travel", "food", "italy"}, rome.Tags)
+	require.Regexp(t, `^# Rome\n\nArrived today\n\n!\[\]\(dayone-moment://ROMEPHOTO\)\n\nPasta again, issue #42 and$`, rome.Text)
+	require.NotContains(t, rome.RichText, "#food")
+}
+
+func TestConvertStripHashtagSpanningRuns(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	inputDir := filepath.Join(tmpDir, "input")
+	outputPath := filepath.Join(tmpDir, "output.zip")
+	entriesDir := filepath.Join(inputDir, "Entries")
+
+	require.NoError(t, os.MkdirAll(entriesDir, 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join(entriesDir, "2024-03-02_Dinner.html"), []byte(
+		`<div class="pageHeader">2 March 2024</div>
+<div class='bodyText'><p>Dinner #<b>food</b>ie <i>tonight</i></p></div>`), 0o600))
+
+	conv := converter.NewConverter(inputDir, "Journal")
+	conv.SetStripHashtags(true)
+	require.NoError(t, conv.Convert(outputPath))
+
+	dinner := readExport(t, outputPath).Entries[0]
+	require.Equal(t, []string{"foodie"}, dinner.Tags)
+	require.Equal(t, "Dinner *tonight*", dinner.Text)
+	require.NotContains(t, dinner.RichText, "food")
+}
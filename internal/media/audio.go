@@ -0,0 +1,209 @@
+package media
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ErrNoDuration is returned for an audio file whose headers give no duration.
+var ErrNoDuration = errors.New("media header has no duration")
+
+// id3HeaderLen is the length of an ID3v2 tag header and footer.
+const id3HeaderLen = 10
+
+// AudioFileDuration returns the duration of the M4A, AAC, MP3 or WAV
+// recording at path.
+func AudioFileDuration(path string) (time.Duration, error) {
+	f, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to open audio")
+	}
+
+	defer func() { _ = f.Close() }() //nolint:errcheck // read-only file close errors are not critical
+
+	return AudioDuration(f)
+}
+
+// AudioDuration reads the duration of an audio recording: from the movie
+// header of an M4A file, the format and data chunks of a WAV file, and the
+// frame headers of an ADTS AAC or MP3 stream, which may follow an ID3 tag.
+func AudioDuration(r io.Reader) (time.Duration, error) {
+	br := bufio.NewReader(r)
+
+	head, err := br.Peek(sniffLen)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return 0, errors.Wrap(err, "failed to read audio header")
+	}
+
+	switch {
+	case len(head) == sniffLen && string(head[:4]) == "RIFF" && string(head[8:12]) == "WAVE":
+		return wavDuration(br)
+	case len(head) == sniffLen && string(head[4:8]) == "ftyp":
+		return m4aDuration(r, br)
+	case bytes.HasPrefix(head, []byte("ID3")):
+		if err := skipID3(br); err != nil {
+			return 0, err
+		}
+	}
+
+	return frameDuration(br)
+}
+
+// m4aDuration reads the movie header of an M4A file. The header may follow
+// the media data, so a seekable r is rewound past what br buffered and handed
+// over unwrapped, letting VideoMetadata seek over the media.
+func m4aDuration(r io.Reader, br *bufio.Reader) (time.Duration, error) {
+	src := io.Reader(br)
+
+	if seeker, ok := r.(io.ReadSeeker); ok {
+		if _, err := seeker.Seek(-int64(br.Buffered()), io.SeekCurrent); err != nil {
+			return 0, errors.Wrap(err, "failed to rewind audio")
+		}
+
+		src = seeker
+	}
+
+	info, err := VideoMetadata(src)
+	if err != nil {
+		return 0, err
+	}
+
+	return info.Duration, nil
+}
+
+// wavDuration divides the size of the data chunk of a WAV file by the byte
+// rate from its format chunk.
+func wavDuration(r *bufio.Reader) (time.Duration, error) {
+	const (
+		riffHeaderLen  = 12
+		chunkHeaderLen = 8
+		fmtLen         = 16
+	)
+
+	if _, err := r.Discard(riffHeaderLen); err != nil {
+		return 0, errors.Wrap(err, "failed to read WAV header")
+	}
+
+	var byteRate uint32
+
+	for {
+		header := make([]byte, chunkHeaderLen)
+		if _, err := io.ReadFull(r, header); err != nil {
+			return 0, errors.Wrap(ErrNoDuration, "no WAV data chunk")
+		}
+
+		size := int(binary.LittleEndian.Uint32(header[4:]))
+
+		switch string(header[:4]) {
+		case "fmt ":
+			format := make([]byte, fmtLen)
+			if _, err := io.ReadFull(r, format); err != nil {
+				return 0, errors.Wrap(err, "failed to read WAV format")
+			}
+
+			byteRate = binary.LittleEndian.Uint32(format[8:])
+			size -= fmtLen
+		case "data":
+			if byteRate == 0 {
+				return 0, errors.Wrap(ErrNoDuration, "no WAV byte rate")
+			}
+
+			return time.Duration(float64(size) / float64(byteRate) * float64(time.Second)), nil
+		}
+
+		if _, err := r.Discard(size + size%2); err != nil {
+			return 0, errors.Wrap(err, "failed to skip WAV chunk")
+		}
+	}
+}
+
+// skipID3 skips an ID3v2 tag.
+func skipID3(r *bufio.Reader) error {
+	const footerFlag = 0x10
+
+	header := make([]byte, id3HeaderLen)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return errors.Wrap(err, "failed to read ID3 tag")
+	}
+
+	// The size is a synchsafe integer of four 7-bit bytes.
+	size := int(header[6])<<21 | int(header[7])<<14 | int(header[8])<<7 | int(header[9])
+	if header[5]&footerFlag != 0 {
+		size += id3HeaderLen
+	}
+
+	if _, err := r.Discard(size); err != nil {
+		return errors.Wrap(err, "failed to skip ID3 tag")
+	}
+
+	return nil
+}
+
+// audioFrame is the part of an ADTS AAC or MPEG audio frame header needed to
+// walk the stream.
+type audioFrame struct {
+	length     int // including the header
+	samples    int
+	sampleRate int
+}
+
+// frameDuration sums the durations of the frames of an ADTS AAC or MP3
+// stream, or takes the frame count from the Xing or Info header of a
+// variable bitrate MP3. Trailing data that is not a frame, such as an ID3v1
+// tag, ends the stream.
+func frameDuration(r *bufio.Reader) (time.Duration, error) {
+	var (
+		seconds float64
+		frames  int
+	)
+
+	for {
+		header, err := r.Peek(adtsHeaderLen)
+		if len(header) < mpegHeaderLen || (err != nil && !errors.Is(err, io.EOF)) {
+			break
+		}
+
+		frame, ok := parseFrameHeader(header)
+		if !ok {
+			break
+		}
+
+		if frames == 0 {
+			if count, ok := xingFrames(r, header); ok {
+				return time.Duration(float64(count*frame.samples) / float64(frame.sampleRate) * float64(time.Second)), nil
+			}
+		}
+
+		if _, err := r.Discard(frame.length); err != nil {
+			break
+		}
+
+		frames++
+		seconds += float64(frame.samples) / float64(frame.sampleRate)
+	}
+
+	if frames == 0 {
+		return 0, ErrUnknownFormat
+	}
+
+	return time.Duration(seconds * float64(time.Second)), nil
+}
+
+func parseFrameHeader(header []byte) (audioFrame, bool) {
+	if header[0] != 0xFF {
+		return audioFrame{}, false
+	}
+
+	if header[1]&0xF6 == 0xF0 {
+		return adtsFrame(header)
+	}
+
+	return mpegFrame(header)
+}
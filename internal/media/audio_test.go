@@ -0,0 +1,181 @@
+package media_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/kpod13/journal2day1/internal/media"
+)
+
+// wav builds a WAV file with dataLen bytes of 16-bit samples, preceded by a
+// LIST chunk of odd length.
+func wav(sampleRate, channels, dataLen uint32) []byte {
+	le := binary.LittleEndian
+
+	format := le.AppendUint16(nil, 1)
+	format = le.AppendUint16(format, uint16(channels)) //nolint:gosec // small test values
+	format = le.AppendUint32(format, sampleRate)
+	format = le.AppendUint32(format, sampleRate*channels*2)
+	format = le.AppendUint16(format, uint16(channels*2)) //nolint:gosec // small test values
+	format = le.AppendUint16(format, 16)
+
+	chunk := func(id string, data []byte) []byte {
+		out := le.AppendUint32([]byte(id), uint32(len(data))) //nolint:gosec // small test values
+		out = append(out, data...)
+
+		if len(data)%2 == 1 {
+			out = append(out, 0)
+		}
+
+		return out
+	}
+
+	body := bytes.Join([][]byte{
+		[]byte("WAVE"),
+		chunk("LIST", []byte("INFO!")),
+		chunk("fmt ", format),
+		chunk("data", make([]byte, dataLen)),
+	}, nil)
+
+	return append(le.AppendUint32([]byte("RIFF"), uint32(len(body))), body...) //nolint:gosec // small test values
+}
+
+// adts builds an AAC stream of frames at 44.1 kHz, each with one raw data
+// block of payloadLen bytes.
+func adts(frames, payloadLen int) []byte {
+	const rateIndex44100 = 4
+
+	length := 7 + payloadLen
+	header := []byte{
+		0xFF, 0xF1,
+		1<<6 | rateIndex44100<<2,
+		2<<6 | byte(length>>11&0x03),
+		byte(length >> 3),
+		byte(length&0x07)<<5 | 0x1F,
+		0xFC,
+	}
+
+	return bytes.Repeat(append(header, make([]byte, payloadLen)...), frames)
+}
+
+// mp3Frame is a 128 kbit/s MPEG 1 layer III stereo frame at 44.1 kHz.
+func mp3Frame(payload []byte) []byte {
+	const frameLen = 417 // 144 * 128000 / 44100
+
+	frame := make([]byte, frameLen)
+	copy(frame, []byte{0xFF, 0xFB, 0x90, 0x00})
+	copy(frame[4:], payload)
+
+	return frame
+}
+
+func id3Tag() []byte {
+	return append([]byte{'I', 'D', '3', 4, 0, 0, 0, 0, 0, 20}, make([]byte, 20)...)
+}
+
+func TestAudioDuration(t *testing.T) {
+	t.Parallel()
+
+	xing := append(append(make([]byte, 32), []byte("Xing")...), be32(1, 1000)...)
+
+	testCases := []struct {
+		name string
+		data []byte
+		want time.Duration
+	}{
+		{
+			name: "wav",
+			data: wav(8000, 2, 8000*2*2*3/2),
+			want: 1500 * time.Millisecond,
+		},
+		{
+			name: "aac",
+			data: adts(441, 10),
+			want: 10240 * time.Millisecond,
+		},
+		{
+			name: "mp3",
+			data: append(append(id3Tag(), bytes.Repeat(mp3Frame(nil), 441)...), []byte("TAG")...),
+			want: 11520 * time.Millisecond,
+		},
+		{
+			name: "variable bitrate mp3",
+			data: append(mp3Frame(xing), bytes.Repeat(mp3Frame(nil), 10)...),
+			want: 1000 * 1152 * time.Second / 44100,
+		},
+		{
+			name: "m4a",
+			data: movie(mvhd(44100, 441000)),
+			want: 10 * time.Second,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			duration, err := media.AudioDuration(bytes.NewReader(tc.data))
+			require.NoError(t, err)
+			require.InDelta(t, tc.want, duration, float64(time.Millisecond))
+		})
+	}
+}
+
+// readCounter counts the bytes read through it.
+type readCounter struct {
+	*bytes.Reader
+
+	read int
+}
+
+func (r *readCounter) Read(p []byte) (int, error) {
+	n, err := r.Reader.Read(p)
+	r.read += n
+
+	return n, err
+}
+
+func TestAudioDurationSeeksOverMediaData(t *testing.T) {
+	t.Parallel()
+
+	const mediaLen = 4 << 20
+
+	data := append(bmffBox("ftyp", []byte("M4A "), be32(0)), bmffBox("mdat", make([]byte, mediaLen))...)
+	data = append(data, bmffBox("moov", mvhd(44100, 441000))...)
+	r := &readCounter{Reader: bytes.NewReader(data)}
+
+	duration, err := media.AudioDuration(r)
+	require.NoError(t, err)
+	require.Equal(t, 10*time.Second, duration)
+	require.Less(t, r.read, mediaLen/64)
+}
+
+func TestAudioDurationErrors(t *testing.T) {
+	t.Parallel()
+
+	_, err := media.AudioDuration(bytes.NewReader([]byte("not an audio file")))
+	require.ErrorIs(t, err, media.ErrUnknownFormat)
+
+	_, err = media.AudioDuration(bytes.NewReader(wav(8000, 1, 0)[:50]))
+	require.ErrorIs(t, err, media.ErrNoDuration)
+}
+
+func TestAudioFileDuration(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "memo.wav")
+	require.NoError(t, os.WriteFile(path, wav(16000, 1, 16000*2*4), 0o600))
+
+	duration, err := media.AudioFileDuration(path)
+	require.NoError(t, err)
+	require.Equal(t, 4*time.Second, duration)
+
+	_, err = media.AudioFileDuration(filepath.Join(t.TempDir(), "missing.wav"))
+	require.Error(t, err)
+}
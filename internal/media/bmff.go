@@ -0,0 +1,228 @@
+package media
+
+import (
+	"encoding/binary"
+	"io"
+
+	"github.com/pkg/errors"
+)
+
+// ISO base media file format (BMFF) boxes, the container of HEIF images and
+// of MP4 and QuickTime videos.
+
+const boxHeaderLen = 8
+
+var errInvalidBox = errors.New("invalid ISO BMFF box")
+
+// box is a box read into memory, without its header.
+type box struct {
+	typ  string
+	data []byte
+}
+
+// readBoxHeader reads the header of the next box and returns its type and
+// payload size. A size of -1 means the box extends to the end of the file.
+func readBoxHeader(r io.Reader) (string, int64, error) {
+	header := make([]byte, boxHeaderLen)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return "", 0, errors.Wrap(err, "failed to read box header")
+	}
+
+	size := int64(binary.BigEndian.Uint32(header))
+	typ := string(header[4:])
+
+	switch size {
+	case 0:
+		return typ, -1, nil
+	case 1:
+		var largeSize uint64
+		if err := binary.Read(r, binary.BigEndian, &largeSize); err != nil {
+			return "", 0, errors.Wrap(err, "failed to read box header")
+		}
+
+		if largeSize < 2*boxHeaderLen || largeSize > 1<<62 {
+			return "", 0, errInvalidBox
+		}
+
+		return typ, int64(largeSize) - 2*boxHeaderLen, nil //nolint:gosec // bounded above
+	}
+
+	if size < boxHeaderLen {
+		return "", 0, errInvalidBox
+	}
+
+	return typ, size - boxHeaderLen, nil
+}
+
+// readTopLevelBox skips the boxes of a file up to the first one of the given
+// type and returns its payload.
+func readTopLevelBox(r io.Reader, typ string, maxSize int64) ([]byte, error) {
+	for {
+		boxType, size, err := readBoxHeader(r)
+		if err != nil {
+			return nil, err
+		}
+
+		if boxType == typ {
+			if size < 0 || size > maxSize {
+				return nil, errors.Wrapf(errInvalidBox, "%s box of unsupported size", typ)
+			}
+
+			data := make([]byte, size)
+			if _, err := io.ReadFull(r, data); err != nil {
+				return nil, errors.Wrapf(err, "failed to read %s box", typ)
+			}
+
+			return data, nil
+		}
+
+		if size < 0 {
+			return nil, errors.Wrapf(errInvalidBox, "no %s box", typ)
+		}
+
+		if err := skip(r, size); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// skip moves past n bytes, seeking when the reader supports it so large
+// boxes such as media data are not read.
+func skip(r io.Reader, n int64) error {
+	if seeker, ok := r.(io.Seeker); ok {
+		_, err := seeker.Seek(n, io.SeekCurrent)
+
+		return errors.Wrap(err, "failed to skip box")
+	}
+
+	_, err := io.CopyN(io.Discard, r, n)
+
+	return errors.Wrap(err, "failed to skip box")
+}
+
+// parseBoxes splits data into consecutive boxes.
+func parseBoxes(data []byte) ([]box, error) {
+	var boxes []box
+
+	for len(data) > 0 {
+		if len(data) < boxHeaderLen {
+			return nil, errInvalidBox
+		}
+
+		size := uint64(binary.BigEndian.Uint32(data))
+		typ := string(data[4:boxHeaderLen])
+		headerLen := uint64(boxHeaderLen)
+
+		switch size {
+		case 0:
+			size = uint64(len(data))
+		case 1:
+			if len(data) < 2*boxHeaderLen {
+				return nil, errInvalidBox
+			}
+
+			size = binary.BigEndian.Uint64(data[boxHeaderLen:])
+			headerLen = 2 * boxHeaderLen
+		}
+
+		if size < headerLen || size > uint64(len(data)) {
+			return nil, errInvalidBox
+		}
+
+		boxes = append(boxes, box{typ: typ, data: data[headerLen:size]})
+		data = data[size:]
+	}
+
+	return boxes, nil
+}
+
+// findBox returns the first box of the given type.
+func findBox(boxes []box, typ string) (box, bool) {
+	for _, b := range boxes {
+		if b.typ == typ {
+			return b, true
+		}
+	}
+
+	return box{}, false
+}
+
+// childBoxes returns the boxes inside the first box of the given type.
+func childBoxes(boxes []box, typ string) ([]box, error) {
+	parent, ok := findBox(boxes, typ)
+	if !ok {
+		return nil, errors.Wrapf(errInvalidBox, "no %s box", typ)
+	}
+
+	return parseBoxes(parent.data)
+}
+
+// fullBoxHeaderLen is the size of the version and flags of a full box.
+const fullBoxHeaderLen = 4
+
+// cursor reads big-endian integers from a box payload. Reads past the end
+// yield zero and set ok to false.
+type cursor struct {
+	data []byte
+	ok   bool
+}
+
+func newCursor(data []byte) *cursor {
+	return &cursor{data: data, ok: true}
+}
+
+func (c *cursor) take(n int) []byte {
+	if !c.ok || len(c.data) < n {
+		c.ok = false
+
+		return make([]byte, n)
+	}
+
+	b := c.data[:n]
+	c.data = c.data[n:]
+
+	return b
+}
+
+func (c *cursor) u8() uint8 {
+	return c.take(1)[0]
+}
+
+func (c *cursor) u16() uint16 {
+	return binary.BigEndian.Uint16(c.take(2))
+}
+
+func (c *cursor) u32() uint32 {
+	return binary.BigEndian.Uint32(c.take(4))
+}
+
+func (c *cursor) u64() uint64 {
+	return binary.BigEndian.Uint64(c.take(8))
+}
+
+// versioned reads a field that is 64 bits wide in version 1 boxes and 32
+// bits wide otherwise.
+func (c *cursor) versioned(version uint8) uint64 {
+	if version == 1 {
+		return c.u64()
+	}
+
+	return uint64(c.u32())
+}
+
+// uintN reads an unsigned integer of 0, 4 or 8 bytes, the field sizes the
+// item location box allows. Zero-sized fields are zero.
+func (c *cursor) uintN(size int) uint64 {
+	switch size {
+	case 4:
+		return uint64(c.u32())
+	case 8:
+		return c.u64()
+	case 0:
+		return 0
+	}
+
+	c.ok = false
+
+	return 0
+}
@@ -0,0 +1,408 @@
+package media
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// EXIF orientations, see the TIFF Orientation tag.
+const (
+	orientationNormal  = orientation(1)
+	orientationMaxFlip = orientation(4) // orientations above this one rotate by 90 or 270 degrees
+)
+
+// TIFF and EXIF tags.
+const (
+	tagMake                = 0x010F
+	tagModel               = 0x0110
+	tagOrientation         = 0x0112
+	tagDateTime            = 0x0132
+	tagExifIFD             = 0x8769
+	tagGPSIFD              = 0x8825
+	tagDateTimeOriginal    = 0x9003
+	tagDateTimeDigitized   = 0x9004
+	tagOffsetTime          = 0x9010
+	tagOffsetTimeOriginal  = 0x9011
+	tagOffsetTimeDigitized = 0x9012
+	tagGPSLatitudeRef      = 0x0001
+	tagGPSLatitude         = 0x0002
+	tagGPSLongitudeRef     = 0x0003
+	tagGPSLongitude        = 0x0004
+)
+
+// exifDateFormat is the layout of EXIF date and time values.
+const exifDateFormat = "2006:01:02 15:04:05"
+
+var errInvalidTIFF = errors.New("invalid TIFF header")
+
+// ErrNoEXIF is returned for images without EXIF metadata.
+var ErrNoEXIF = errors.New("image has no EXIF metadata")
+
+// EXIF is the capture metadata of a photo.
+type EXIF struct {
+	Make        string
+	Model       string
+	HasLocation bool
+	Latitude    float64 // degrees, negative south of the equator
+	Longitude   float64 // degrees, negative west of Greenwich
+	dateTime    string  // local capture time, exifDateFormat
+	offset      string  // offset of dateTime from UTC, e.g. "+02:00", if recorded
+}
+
+// CaptureTime returns when the photo was taken. Times recorded without an
+// offset from UTC are read in loc.
+func (e *EXIF) CaptureTime(loc *time.Location) (time.Time, bool) {
+	if e.dateTime == "" {
+		return time.Time{}, false
+	}
+
+	if e.offset != "" {
+		if t, err := time.Parse(exifDateFormat+"-07:00", e.dateTime+e.offset); err == nil {
+			return t, true
+		}
+	}
+
+	t, err := time.ParseInLocation(exifDateFormat, e.dateTime, loc)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	return t, true
+}
+
+// Device returns the camera, e.g. "Apple iPhone 15 Pro". The make is left
+// out when the model already starts with it.
+func (e *EXIF) Device() string {
+	if e.Make == "" || strings.HasPrefix(strings.ToLower(e.Model), strings.ToLower(e.Make)) {
+		return e.Model
+	}
+
+	return strings.TrimSpace(e.Make + " " + e.Model)
+}
+
+// FileEXIF returns the EXIF metadata of the JPEG or HEIC image at path.
+func FileEXIF(path string) (*EXIF, error) {
+	f, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open image")
+	}
+
+	defer func() { _ = f.Close() }() //nolint:errcheck // read-only file close errors are not critical
+
+	return ReadEXIF(f)
+}
+
+// ReadEXIF reads the EXIF metadata of a JPEG or HEIC image. HEIC files keep
+// it in an item that may lie anywhere in the file, hence the io.Seeker.
+func ReadEXIF(r io.ReadSeeker) (*EXIF, error) {
+	head := make([]byte, sniffLen)
+	if _, err := io.ReadFull(r, head); err != nil {
+		return nil, errors.Wrap(err, "failed to read image header")
+	}
+
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return nil, errors.Wrap(err, "failed to rewind image")
+	}
+
+	var (
+		data []byte
+		err  error
+	)
+
+	switch {
+	case bytes.HasPrefix(head, []byte{0xFF, 0xD8}):
+		data, _, err = readJPEGHeader(bufio.NewReader(r))
+		if data != nil && errors.Is(err, ErrNoDimensions) {
+			err = nil
+		}
+	case string(head[4:8]) == "ftyp" && isHEIFBrand(string(head[8:12])):
+		data, err = heifEXIF(r)
+	default:
+		return nil, ErrUnknownFormat
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if data == nil {
+		return nil, ErrNoEXIF
+	}
+
+	return parseEXIF(data)
+}
+
+// parseEXIF reads EXIF data that starts with a TIFF header.
+func parseEXIF(data []byte) (*EXIF, error) {
+	t, err := newTIFF(data)
+	if err != nil {
+		return nil, err
+	}
+
+	ifd0, err := t.ifd(t.firstIFD())
+	if err != nil {
+		return nil, err
+	}
+
+	exif := &EXIF{
+		Make:     t.string(ifd0, tagMake),
+		Model:    t.string(ifd0, tagModel),
+		dateTime: t.string(ifd0, tagDateTime),
+	}
+
+	if sub := t.subIFD(ifd0, tagExifIFD); sub != nil {
+		if exif.dateTime != "" {
+			exif.offset = t.string(sub, tagOffsetTime)
+		}
+
+		// The original capture time wins over the digitized and modified ones.
+		// Each has an offset tag of its own.
+		for _, tags := range [][2]uint16{
+			{tagDateTimeDigitized, tagOffsetTimeDigitized},
+			{tagDateTimeOriginal, tagOffsetTimeOriginal},
+		} {
+			if value := t.string(sub, tags[0]); value != "" {
+				exif.dateTime, exif.offset = value, t.string(sub, tags[1])
+			}
+		}
+	}
+
+	if gps := t.subIFD(ifd0, tagGPSIFD); gps != nil {
+		exif.Latitude, exif.Longitude, exif.HasLocation = t.gpsPosition(gps)
+	}
+
+	return exif, nil
+}
+
+// orientation is the EXIF orientation of an image, 1 to 8.
+type orientation int
+
+// swapsAxes reports whether the orientation turns the image by a quarter.
+func (o orientation) swapsAxes() bool {
+	return o > orientationMaxFlip
+}
+
+// tiff reads the image file directories of a TIFF structure, which is how
+// EXIF metadata is stored.
+type tiff struct {
+	data  []byte
+	order binary.ByteOrder
+}
+
+// ifdEntry is a tag of an image file directory with its raw value.
+type ifdEntry struct {
+	tag   uint16
+	typ   uint16
+	count uint32
+	value []byte
+}
+
+// TIFF field types and their sizes in bytes.
+const (
+	typeASCII    = 2
+	typeShort    = 3
+	typeLong     = 4
+	typeRational = 5
+)
+
+var typeSizes = map[uint16]uint32{1: 1, 2: 1, 3: 2, 4: 4, 5: 8, 7: 1, 9: 4, 10: 8}
+
+func newTIFF(data []byte) (*tiff, error) {
+	const headerLen = 8
+
+	if len(data) < headerLen {
+		return nil, errInvalidTIFF
+	}
+
+	switch string(data[:4]) {
+	case "II*\x00":
+		return &tiff{data: data, order: binary.LittleEndian}, nil
+	case "MM\x00*":
+		return &tiff{data: data, order: binary.BigEndian}, nil
+	}
+
+	return nil, errInvalidTIFF
+}
+
+// firstIFD returns the offset of the first image file directory.
+func (t *tiff) firstIFD() uint32 {
+	return t.order.Uint32(t.data[4:])
+}
+
+// ifd reads the entries of the image file directory at offset. Entries whose
+// values lie outside the data are left out.
+func (t *tiff) ifd(offset uint32) ([]ifdEntry, error) {
+	const entryLen = 12
+
+	if uint64(offset)+2 > uint64(len(t.data)) {
+		return nil, errInvalidTIFF
+	}
+
+	count := int(t.order.Uint16(t.data[offset:]))
+	start := int(offset) + 2 //nolint:gosec // offset is bounded by len(t.data)
+
+	if start+count*entryLen > len(t.data) {
+		return nil, errInvalidTIFF
+	}
+
+	entries := make([]ifdEntry, 0, count)
+
+	for i := range count {
+		raw := t.data[start+i*entryLen:]
+		entry := ifdEntry{
+			tag:   t.order.Uint16(raw),
+			typ:   t.order.Uint16(raw[2:]),
+			count: t.order.Uint32(raw[4:]),
+		}
+
+		size := uint64(typeSizes[entry.typ]) * uint64(entry.count)
+
+		switch {
+		case size <= 4:
+			entry.value = raw[8 : 8+size]
+		case uint64(t.order.Uint32(raw[8:]))+size <= uint64(len(t.data)):
+			valueOffset := uint64(t.order.Uint32(raw[8:]))
+			entry.value = t.data[valueOffset : valueOffset+size]
+		default:
+			continue
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+// uint returns the first value of a SHORT or LONG entry.
+func (t *tiff) uint(entry ifdEntry) (uint32, bool) {
+	switch {
+	case entry.typ == typeShort && len(entry.value) >= 2:
+		return uint32(t.order.Uint16(entry.value)), true
+	case entry.typ == typeLong && len(entry.value) >= 4:
+		return t.order.Uint32(entry.value), true
+	}
+
+	return 0, false
+}
+
+func findEntry(entries []ifdEntry, tag uint16) (ifdEntry, bool) {
+	for _, entry := range entries {
+		if entry.tag == tag {
+			return entry, true
+		}
+	}
+
+	return ifdEntry{}, false
+}
+
+// string returns the value of an ASCII entry without trailing NULs and
+// spaces, or an empty string.
+func (t *tiff) string(entries []ifdEntry, tag uint16) string {
+	entry, ok := findEntry(entries, tag)
+	if !ok || entry.typ != typeASCII {
+		return ""
+	}
+
+	return strings.TrimRight(string(entry.value), "\x00 ")
+}
+
+// subIFD returns the entries of the directory an entry points to, or nil.
+func (t *tiff) subIFD(entries []ifdEntry, tag uint16) []ifdEntry {
+	entry, ok := findEntry(entries, tag)
+	if !ok {
+		return nil
+	}
+
+	offset, ok := t.uint(entry)
+	if !ok {
+		return nil
+	}
+
+	sub, err := t.ifd(offset)
+	if err != nil {
+		return nil
+	}
+
+	return sub
+}
+
+// rationals returns the values of a RATIONAL entry.
+func (t *tiff) rationals(entry ifdEntry) []float64 {
+	if entry.typ != typeRational {
+		return nil
+	}
+
+	values := make([]float64, 0, entry.count)
+
+	for i := 0; i+8 <= len(entry.value); i += 8 {
+		numerator, denominator := t.order.Uint32(entry.value[i:]), t.order.Uint32(entry.value[i+4:])
+		if denominator == 0 {
+			return nil
+		}
+
+		values = append(values, float64(numerator)/float64(denominator))
+	}
+
+	return values
+}
+
+// gpsPosition returns the latitude and longitude of a GPS directory in
+// degrees.
+func (t *tiff) gpsPosition(gps []ifdEntry) (latitude, longitude float64, ok bool) {
+	latitude, latOK := t.gpsCoordinate(gps, tagGPSLatitude, tagGPSLatitudeRef, "S")
+	longitude, lonOK := t.gpsCoordinate(gps, tagGPSLongitude, tagGPSLongitudeRef, "W")
+
+	return latitude, longitude, latOK && lonOK
+}
+
+// gpsCoordinate converts degrees, minutes and seconds to degrees, negated
+// for the given hemisphere reference.
+func (t *tiff) gpsCoordinate(gps []ifdEntry, tag, refTag uint16, negativeRef string) (float64, bool) {
+	entry, ok := findEntry(gps, tag)
+	if !ok {
+		return 0, false
+	}
+
+	dms := t.rationals(entry)
+	if len(dms) != 3 {
+		return 0, false
+	}
+
+	degrees := dms[0] + dms[1]/60 + dms[2]/3600
+
+	if t.string(gps, refTag) == negativeRef {
+		degrees = -degrees
+	}
+
+	return degrees, true
+}
+
+// exifOrientation returns the orientation recorded in EXIF data that starts
+// with a TIFF header, or the normal orientation.
+func exifOrientation(data []byte) orientation {
+	t, err := newTIFF(data)
+	if err != nil {
+		return orientationNormal
+	}
+
+	entries, err := t.ifd(t.firstIFD())
+	if err != nil {
+		return orientationNormal
+	}
+
+	if entry, ok := findEntry(entries, tagOrientation); ok {
+		if value, ok := t.uint(entry); ok && value >= 1 && value <= 8 {
+			return orientation(value)
+		}
+	}
+
+	return orientationNormal
+}
@@ -0,0 +1,324 @@
+package media_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/kpod13/journal2day1/internal/media"
+)
+
+// tiffEntry is a TIFF tag for buildTIFF. A non-zero ref points the tag to
+// the directory with that 1-based index.
+type tiffEntry struct {
+	tag   uint16
+	typ   uint16
+	count uint32
+	value []byte
+	ref   int
+}
+
+func asciiTag(tag uint16, value string) tiffEntry {
+	return tiffEntry{tag: tag, typ: 2, count: uint32(len(value) + 1), value: append([]byte(value), 0)}
+}
+
+func shortTag(tag, value uint16) tiffEntry {
+	return tiffEntry{tag: tag, typ: 3, count: 1, value: binary.LittleEndian.AppendUint16(nil, value)}
+}
+
+func rationalTag(tag uint16, values ...uint32) tiffEntry {
+	var data []byte
+
+	for _, v := range values {
+		data = binary.LittleEndian.AppendUint32(data, v)
+		data = binary.LittleEndian.AppendUint32(data, 1)
+	}
+
+	return tiffEntry{tag: tag, typ: 5, count: uint32(len(values)), value: data}
+}
+
+func ifdRef(tag uint16, ref int) tiffEntry {
+	return tiffEntry{tag: tag, typ: 4, count: 1, ref: ref}
+}
+
+// buildTIFF lays out a little-endian TIFF structure: the header, the
+// directories in order, then the values that do not fit into their entries.
+func buildTIFF(ifds ...[]tiffEntry) []byte {
+	offsets := make([]uint32, len(ifds))
+	next := uint32(8)
+
+	for i, ifd := range ifds {
+		offsets[i] = next
+		next += uint32(2 + 12*len(ifd) + 4)
+	}
+
+	out := append([]byte("II*\x00"), binary.LittleEndian.AppendUint32(nil, 8)...)
+
+	var values []byte
+
+	for _, ifd := range ifds {
+		out = binary.LittleEndian.AppendUint16(out, uint16(len(ifd)))
+
+		for _, e := range ifd {
+			out = binary.LittleEndian.AppendUint16(out, e.tag)
+			out = binary.LittleEndian.AppendUint16(out, e.typ)
+			out = binary.LittleEndian.AppendUint32(out, e.count)
+
+			switch {
+			case e.ref > 0:
+				out = binary.LittleEndian.AppendUint32(out, offsets[e.ref-1])
+			case len(e.value) <= 4:
+				out = append(out, append(e.value, make([]byte, 4-len(e.value))...)...)
+			default:
+				out = binary.LittleEndian.AppendUint32(out, next+uint32(len(values)))
+				values = append(values, e.value...)
+			}
+		}
+
+		out = binary.LittleEndian.AppendUint32(out, 0)
+	}
+
+	return append(out, values...)
+}
+
+// withEXIF inserts an APP1 segment with the TIFF data after the start of
+// image marker of a JPEG.
+func withEXIF(jpegData, tiff []byte) []byte {
+	payload := append([]byte("Exif\x00\x00"), tiff...)
+	segment := binary.BigEndian.AppendUint16([]byte{0xFF, 0xE1}, uint16(len(payload)+2))
+	segment = append(segment, payload...)
+
+	out := append([]byte{}, jpegData[:2]...)
+	out = append(out, segment...)
+
+	return append(out, jpegData[2:]...)
+}
+
+func withEXIFOrientation(jpegData []byte, orientation uint16) []byte {
+	return withEXIF(jpegData, buildTIFF([]tiffEntry{shortTag(0x0112, orientation)}))
+}
+
+func cameraTIFF(offset string, north, east bool) []byte {
+	latRef, lonRef := "N", "E"
+	if !north {
+		latRef = "S"
+	}
+
+	if !east {
+		lonRef = "W"
+	}
+
+	exifIFD := []tiffEntry{asciiTag(0x9003, "2024:05:04 10:11:12")}
+	if offset != "" {
+		exifIFD = append(exifIFD, asciiTag(0x9011, offset))
+	}
+
+	return buildTIFF(
+		[]tiffEntry{
+			asciiTag(0x010F, "Apple"),
+			asciiTag(0x0110, "iPhone 15 Pro"),
+			asciiTag(0x0132, "2024:06:01 00:00:00"),
+			ifdRef(0x8769, 2),
+			ifdRef(0x8825, 3),
+		},
+		exifIFD,
+		[]tiffEntry{
+			asciiTag(0x0001, latRef),
+			rationalTag(0x0002, 41, 54, 36),
+			asciiTag(0x0003, lonRef),
+			rationalTag(0x0004, 12, 30, 0),
+		},
+	)
+}
+
+// heicWithEXIF builds a HEIF file whose Exif item is stored in the media
+// data (mdat) box or, with inIdat, in the item data (idat) box.
+func heicWithEXIF(tiff []byte, inIdat bool) []byte {
+	payload := append(binary.BigEndian.AppendUint32(nil, 6), "Exif\x00\x00"...)
+	payload = append(payload, tiff...)
+
+	ftyp := bmffBox("ftyp", []byte("heic"), []byte{0, 0, 0, 0}, []byte("mif1heic"))
+	iinf := fullBox("iinf", []byte{0, 2},
+		bmffBox("infe", []byte{2, 0, 0, 0, 0, 1, 0, 0}, []byte("hvc1")),
+		bmffBox("infe", []byte{2, 0, 0, 0, 0, 2, 0, 0}, []byte("Exif")),
+	)
+
+	meta := func(offset uint32) []byte {
+		method := []byte{0, 0}
+
+		var idat []byte
+
+		if inIdat {
+			method, idat = []byte{0, 1}, bmffBox("idat", payload)
+		}
+
+		iloc := bmffBox("iloc", []byte{1, 0, 0, 0, 0x44, 0x00, 0, 1, 0, 2}, method, []byte{0, 0, 0, 1},
+			be32(offset, uint32(len(payload))))
+
+		return fullBox("meta", fullBox("hdlr", make([]byte, 20)), fullBox("pitm", []byte{0, 1}), iinf, iloc, idat)
+	}
+
+	if inIdat {
+		return append(ftyp, meta(0)...)
+	}
+
+	offset := uint32(len(ftyp) + len(meta(0)) + 8)
+
+	return bytes.Join([][]byte{ftyp, meta(offset), bmffBox("mdat", payload)}, nil)
+}
+
+func TestReadEXIF(t *testing.T) {
+	t.Parallel()
+
+	jpegData := encodedImage(t, encodeJPEG, 6, 4)
+	rome := time.Date(2024, 5, 4, 8, 11, 12, 0, time.UTC)
+
+	testCases := []struct {
+		name string
+		data []byte
+	}{
+		{name: "jpeg", data: withEXIF(jpegData, cameraTIFF("+02:00", true, true))},
+		{name: "heic", data: heicWithEXIF(cameraTIFF("+02:00", true, true), false)},
+		{name: "heic idat", data: heicWithEXIF(cameraTIFF("+02:00", true, true), true)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			exif, err := media.ReadEXIF(bytes.NewReader(tc.data))
+			require.NoError(t, err)
+
+			captured, ok := exif.CaptureTime(time.UTC)
+			require.True(t, ok)
+			require.True(t, rome.Equal(captured), captured)
+			require.Equal(t, "Apple iPhone 15 Pro", exif.Device())
+			require.True(t, exif.HasLocation)
+			require.InDelta(t, 41.91, exif.Latitude, 1e-9)
+			require.InDelta(t, 12.5, exif.Longitude, 1e-9)
+		})
+	}
+}
+
+func TestReadEXIFWithoutOffset(t *testing.T) {
+	t.Parallel()
+
+	data := withEXIF(encodedImage(t, encodeJPEG, 6, 4), cameraTIFF("", false, false))
+
+	exif, err := media.ReadEXIF(bytes.NewReader(data))
+	require.NoError(t, err)
+
+	newYork, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+
+	captured, ok := exif.CaptureTime(newYork)
+	require.True(t, ok)
+	require.Equal(t, time.Date(2024, 5, 4, 14, 11, 12, 0, time.UTC), captured.UTC(), "local time read in the given zone")
+	require.InDelta(t, -41.91, exif.Latitude, 1e-9)
+	require.InDelta(t, -12.5, exif.Longitude, 1e-9)
+}
+
+func TestEXIFDevice(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, "Canon EOS R5", (&media.EXIF{Make: "Canon", Model: "Canon EOS R5"}).Device())
+	require.Equal(t, "FUJIFILM X-T4", (&media.EXIF{Make: "FUJIFILM", Model: "X-T4"}).Device())
+	require.Equal(t, "Pixel 8", (&media.EXIF{Model: "Pixel 8"}).Device())
+
+	_, ok := (&media.EXIF{}).CaptureTime(time.UTC)
+	require.False(t, ok)
+}
+
+func TestReadEXIFErrors(t *testing.T) {
+	t.Parallel()
+
+	_, err := media.ReadEXIF(bytes.NewReader(encodedImage(t, encodeJPEG, 6, 4)))
+	require.ErrorIs(t, err, media.ErrNoEXIF)
+
+	_, err = media.ReadEXIF(bytes.NewReader(encodedImage(t, encodePNG, 6, 4)))
+	require.ErrorIs(t, err, media.ErrUnknownFormat)
+
+	_, err = media.ReadEXIF(bytes.NewReader(heicImage(4032, 3024, 0)))
+	require.ErrorIs(t, err, media.ErrNoEXIF)
+
+	_, err = media.FileEXIF("/nonexistent/photo.jpg")
+	require.Error(t, err)
+}
+
+func TestReadEXIFOverflowingIdatExtent(t *testing.T) {
+	t.Parallel()
+
+	// The extent's offset plus its length wraps around to 4, within the idat.
+	ftyp := bmffBox("ftyp", []byte("heic"), []byte{0, 0, 0, 0}, []byte("mif1heic"))
+	iinf := fullBox("iinf", []byte{0, 1}, bmffBox("infe", []byte{2, 0, 0, 0, 0, 2, 0, 0}, []byte("Exif")))
+	iloc := bmffBox("iloc", []byte{1, 0, 0, 0, 0x88, 0x00, 0, 1, 0, 2, 0, 1, 0, 0, 0, 1},
+		binary.BigEndian.AppendUint64(nil, math.MaxUint64-3), binary.BigEndian.AppendUint64(nil, 8))
+	meta := fullBox("meta", fullBox("hdlr", make([]byte, 20)), iinf, iloc, bmffBox("idat", make([]byte, 16)))
+
+	require.NotPanics(t, func() {
+		_, err := media.ReadEXIF(bytes.NewReader(append(ftyp, meta...)))
+		require.Error(t, err)
+	})
+}
+
+func TestEXIFOffsetPairsWithDate(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name    string
+		ifd0    []tiffEntry
+		exifIFD []tiffEntry
+		want    time.Time
+	}{
+		{
+			name: "original",
+			exifIFD: []tiffEntry{
+				asciiTag(0x9003, "2024:05:04 10:00:00"),
+				asciiTag(0x9004, "2024:05:04 11:00:00"),
+				asciiTag(0x9010, "+05:00"),
+				asciiTag(0x9011, "+02:00"),
+				asciiTag(0x9012, "+03:00"),
+			},
+			want: time.Date(2024, 5, 4, 8, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "digitized",
+			exifIFD: []tiffEntry{
+				asciiTag(0x9004, "2024:05:04 11:00:00"),
+				asciiTag(0x9011, "+02:00"),
+				asciiTag(0x9012, "+03:00"),
+			},
+			want: time.Date(2024, 5, 4, 8, 0, 0, 0, time.UTC),
+		},
+		{
+			name:    "modified",
+			ifd0:    []tiffEntry{asciiTag(0x0132, "2024:05:04 12:00:00")},
+			exifIFD: []tiffEntry{asciiTag(0x9010, "-04:00"), asciiTag(0x9011, "+02:00")},
+			want:    time.Date(2024, 5, 4, 16, 0, 0, 0, time.UTC),
+		},
+		{
+			name:    "digitized without its offset",
+			exifIFD: []tiffEntry{asciiTag(0x9004, "2024:05:04 11:00:00"), asciiTag(0x9011, "+02:00")},
+			want:    time.Date(2024, 5, 4, 11, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			tiff := buildTIFF(append(tc.ifd0, ifdRef(0x8769, 2)), tc.exifIFD)
+
+			exif, err := media.ReadEXIF(bytes.NewReader(withEXIF(encodedImage(t, encodeJPEG, 6, 4), tiff)))
+			require.NoError(t, err)
+
+			captured, ok := exif.CaptureTime(time.UTC)
+			require.True(t, ok)
+			require.Equal(t, tc.want, captured.UTC())
+		})
+	}
+}
@@ -0,0 +1,349 @@
+package media
+
+import (
+	"bytes"
+	"io"
+	"slices"
+
+	"github.com/pkg/errors"
+)
+
+// maxMetaSize bounds the HEIF meta box read into memory.
+const maxMetaSize = 16 << 20
+
+// heifBrands are the ftyp brands of HEIF still images.
+var heifBrands = []string{"heic", "heix", "heim", "heis", "hevc", "hevx", "mif1", "msf1", "avif"}
+
+func isHEIFBrand(brand string) bool {
+	return slices.Contains(heifBrands, brand)
+}
+
+// heifDimensions reads the size of the primary image from the image spatial
+// extents (ispe) property and applies its rotation (irot).
+func heifDimensions(r io.Reader) (Dimensions, error) {
+	meta, err := readTopLevelBox(r, "meta", maxMetaSize)
+	if err != nil {
+		return Dimensions{}, errors.Wrap(err, "failed to read HEIF metadata")
+	}
+
+	if len(meta) < fullBoxHeaderLen {
+		return Dimensions{}, errInvalidBox
+	}
+
+	boxes, err := parseBoxes(meta[fullBoxHeaderLen:])
+	if err != nil {
+		return Dimensions{}, err
+	}
+
+	properties, err := primaryItemProperties(boxes)
+	if err != nil {
+		return Dimensions{}, err
+	}
+
+	var (
+		dims    Dimensions
+		rotated bool
+	)
+
+	for _, property := range properties {
+		switch property.typ {
+		case "ispe":
+			c := newCursor(property.data)
+			c.take(fullBoxHeaderLen)
+			dims = Dimensions{Width: int(c.u32()), Height: int(c.u32())}
+		case "irot":
+			rotated = len(property.data) > 0 && property.data[0]&1 == 1 // odd quarter turns
+		}
+	}
+
+	if dims.Width == 0 || dims.Height == 0 {
+		return Dimensions{}, ErrNoDimensions
+	}
+
+	return dims.rotate(rotated), nil
+}
+
+// primaryItemProperties returns the properties associated with the primary
+// item in the item property association (ipma) box, in association order.
+func primaryItemProperties(meta []box) ([]box, error) {
+	pitm, ok := findBox(meta, "pitm")
+	if !ok {
+		return nil, errors.Wrap(errInvalidBox, "no pitm box")
+	}
+
+	c := newCursor(pitm.data)
+	version := c.u8()
+	c.take(3) // flags
+
+	primary := uint32(c.u16())
+	if version > 0 {
+		primary = primary<<16 | uint32(c.u16())
+	}
+
+	iprp, err := childBoxes(meta, "iprp")
+	if err != nil {
+		return nil, err
+	}
+
+	ipco, err := childBoxes(iprp, "ipco")
+	if err != nil {
+		return nil, err
+	}
+
+	ipma, ok := findBox(iprp, "ipma")
+	if !ok {
+		return nil, errors.Wrap(errInvalidBox, "no ipma box")
+	}
+
+	var properties []box
+
+	for _, index := range associations(ipma.data, primary) {
+		if index >= 1 && index <= len(ipco) {
+			properties = append(properties, ipco[index-1])
+		}
+	}
+
+	return properties, nil
+}
+
+// associations returns the 1-based ipco indexes of an item's properties.
+func associations(ipma []byte, item uint32) []int {
+	c := newCursor(ipma)
+	version := c.u8()
+	flags := c.take(3)
+	wideIndexes := flags[2]&1 == 1
+	count := c.u32()
+
+	for range count {
+		var id uint32
+		if version < 1 {
+			id = uint32(c.u16())
+		} else {
+			id = c.u32()
+		}
+
+		n := int(c.u8())
+		indexes := make([]int, 0, n)
+
+		for range n {
+			if wideIndexes {
+				indexes = append(indexes, int(c.u16()&0x7FFF))
+			} else {
+				indexes = append(indexes, int(c.u8()&0x7F))
+			}
+		}
+
+		if !c.ok {
+			return nil
+		}
+
+		if id == item {
+			return indexes
+		}
+	}
+
+	return nil
+}
+
+// heifEXIF returns the EXIF data of a HEIF image, starting with its TIFF
+// header, or nil when the image has none.
+func heifEXIF(r io.ReadSeeker) ([]byte, error) {
+	meta, err := readTopLevelBox(r, "meta", maxMetaSize)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read HEIF metadata")
+	}
+
+	if len(meta) < fullBoxHeaderLen {
+		return nil, errInvalidBox
+	}
+
+	boxes, err := parseBoxes(meta[fullBoxHeaderLen:])
+	if err != nil {
+		return nil, err
+	}
+
+	item, ok := exifItem(boxes)
+	if !ok {
+		return nil, nil
+	}
+
+	data, err := readItem(r, boxes, item)
+	if err != nil {
+		return nil, err
+	}
+
+	// The item starts with the offset of the TIFF header after this field.
+	c := newCursor(data)
+	offset := uint64(c.u32()) + 4
+
+	if !c.ok || offset > uint64(len(data)) {
+		return nil, errInvalidBox
+	}
+
+	return bytes.TrimPrefix(data[offset:], exifHeader), nil
+}
+
+// exifItem returns the ID of the Exif item listed in the item information
+// (iinf) box.
+func exifItem(meta []box) (uint32, bool) {
+	iinf, ok := findBox(meta, "iinf")
+	if !ok {
+		return 0, false
+	}
+
+	c := newCursor(iinf.data)
+	version := c.u8()
+	c.take(3) // flags
+
+	if version == 0 {
+		c.u16() // entry count
+	} else {
+		c.u32()
+	}
+
+	if !c.ok {
+		return 0, false
+	}
+
+	entries, err := parseBoxes(c.data)
+	if err != nil {
+		return 0, false
+	}
+
+	for _, infe := range entries {
+		if infe.typ != "infe" {
+			continue
+		}
+
+		e := newCursor(infe.data)
+		infeVersion := e.u8()
+		e.take(3) // flags
+
+		if infeVersion < 2 { // without item types
+			continue
+		}
+
+		id := uint32(e.u16())
+		if infeVersion > 2 {
+			id = id<<16 | uint32(e.u16())
+		}
+
+		e.u16() // protection index
+
+		if string(e.take(4)) == "Exif" && e.ok {
+			return id, true
+		}
+	}
+
+	return 0, false
+}
+
+// readItem reads the extents of an item from the file or from the item data
+// (idat) box, as listed in the item location (iloc) box.
+func readItem(r io.ReadSeeker, meta []box, item uint32) ([]byte, error) {
+	iloc, ok := findBox(meta, "iloc")
+	if !ok {
+		return nil, errors.Wrap(errInvalidBox, "no iloc box")
+	}
+
+	c := newCursor(iloc.data)
+	version := c.u8()
+	c.take(3) // flags
+
+	sizes := c.u16()
+	offsetSize, lengthSize := int(sizes>>12), int(sizes>>8&0xF)
+	baseOffsetSize, indexSize := int(sizes>>4&0xF), int(sizes&0xF)
+
+	if version == 0 {
+		indexSize = 0
+	}
+
+	count := uint32(c.u16())
+	if version == 2 {
+		count = count<<16 | uint32(c.u16())
+	}
+
+	for range count {
+		id := uint32(c.u16())
+		if version == 2 {
+			id = id<<16 | uint32(c.u16())
+		}
+
+		method := uint16(0)
+		if version > 0 {
+			method = c.u16() & 0xF
+		}
+
+		c.u16() // data reference index
+		base := c.uintN(baseOffsetSize)
+		extents := make([][2]uint64, c.u16())
+
+		for i := range extents {
+			c.uintN(indexSize)
+			extents[i] = [2]uint64{base + c.uintN(offsetSize), c.uintN(lengthSize)}
+		}
+
+		if !c.ok {
+			break
+		}
+
+		if id == item {
+			return readExtents(r, meta, method, extents)
+		}
+	}
+
+	return nil, errors.Wrap(errInvalidBox, "item not located")
+}
+
+// Item construction methods.
+const (
+	constructionFile = 0
+	constructionIdat = 1
+)
+
+// maxItemSize bounds the size of an item read into memory.
+const maxItemSize = 16 << 20
+
+func readExtents(r io.ReadSeeker, meta []box, method uint16, extents [][2]uint64) ([]byte, error) {
+	if method != constructionFile && method != constructionIdat {
+		return nil, errors.Wrapf(errInvalidBox, "unsupported construction method %d", method)
+	}
+
+	idat, ok := findBox(meta, "idat")
+	if method == constructionIdat && !ok {
+		return nil, errors.Wrap(errInvalidBox, "no idat box")
+	}
+
+	var data []byte
+
+	for _, extent := range extents {
+		offset, length := extent[0], extent[1]
+
+		if length > maxItemSize || uint64(len(data))+length > maxItemSize {
+			return nil, errors.Wrap(errInvalidBox, "item too large")
+		}
+
+		if method == constructionIdat {
+			if offset > uint64(len(idat.data)) || length > uint64(len(idat.data))-offset {
+				return nil, errInvalidBox
+			}
+
+			data = append(data, idat.data[offset:offset+length]...)
+
+			continue
+		}
+
+		if _, err := r.Seek(int64(offset), io.SeekStart); err != nil { //nolint:gosec // offsets beyond the file fail to read
+			return nil, errors.Wrap(err, "failed to seek to item")
+		}
+
+		chunk := make([]byte, length)
+		if _, err := io.ReadFull(r, chunk); err != nil {
+			return nil, errors.Wrap(err, "failed to read item")
+		}
+
+		data = append(data, chunk...)
+	}
+
+	return data, nil
+}
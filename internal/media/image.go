@@ -0,0 +1,140 @@
+// Package media reads metadata from the headers of photo, video and audio
+// files without decoding their contents.
+package media
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/gif"
+	"image/png"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+)
+
+// Sentinel errors for media headers.
+var (
+	ErrUnknownFormat = errors.New("unknown media format")
+	ErrNoDimensions  = errors.New("media header has no dimensions")
+)
+
+// sniffLen is the number of leading bytes needed to detect an image format.
+const sniffLen = 12
+
+// Dimensions is the displayed size of an image or video in pixels.
+type Dimensions struct {
+	Width  int
+	Height int
+}
+
+// rotate swaps width and height for rotations by 90 or 270 degrees.
+func (d Dimensions) rotate(quarterTurns bool) Dimensions {
+	if quarterTurns {
+		return Dimensions{Width: d.Height, Height: d.Width}
+	}
+
+	return d
+}
+
+// ImageFileDimensions returns the dimensions of the image at path.
+func ImageFileDimensions(path string) (Dimensions, error) {
+	f, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return Dimensions{}, errors.Wrap(err, "failed to open image")
+	}
+
+	defer func() { _ = f.Close() }() //nolint:errcheck // read-only file close errors are not critical
+
+	return ImageDimensions(f)
+}
+
+// ImageDimensions reads the dimensions of a JPEG, PNG, GIF, WebP or HEIC/HEIF
+// image from its header. Rotations recorded in EXIF or HEIF metadata are
+// applied, so portraits report a larger height than width.
+func ImageDimensions(r io.Reader) (Dimensions, error) {
+	br := bufio.NewReader(r)
+
+	head, err := br.Peek(sniffLen)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return Dimensions{}, errors.Wrap(err, "failed to read image header")
+	}
+
+	switch {
+	case bytes.HasPrefix(head, []byte{0xFF, 0xD8}):
+		return jpegDimensions(br)
+	case bytes.HasPrefix(head, []byte("\x89PNG\r\n\x1a\n")):
+		return decodeConfig(png.DecodeConfig(br))
+	case bytes.HasPrefix(head, []byte("GIF8")):
+		return decodeConfig(gif.DecodeConfig(br))
+	case len(head) == sniffLen && string(head[:4]) == "RIFF" && string(head[8:12]) == "WEBP":
+		return webpDimensions(br)
+	case len(head) == sniffLen && string(head[4:8]) == "ftyp" && isHEIFBrand(string(head[8:12])):
+		return heifDimensions(br)
+	}
+
+	return Dimensions{}, ErrUnknownFormat
+}
+
+func decodeConfig(cfg image.Config, err error) (Dimensions, error) {
+	if err != nil {
+		return Dimensions{}, errors.Wrap(err, "failed to read image header")
+	}
+
+	return Dimensions{Width: cfg.Width, Height: cfg.Height}, nil
+}
+
+// webpDimensions reads the canvas size from the first chunk of a lossy,
+// lossless or extended WebP file.
+func webpDimensions(r io.Reader) (Dimensions, error) {
+	const (
+		lossyLen    = 30
+		losslessLen = 25
+	)
+
+	head := make([]byte, lossyLen)
+
+	n, err := io.ReadFull(r, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return Dimensions{}, errors.Wrap(err, "failed to read WebP header")
+	}
+
+	if n < losslessLen {
+		return Dimensions{}, ErrNoDimensions
+	}
+
+	switch string(head[12:16]) {
+	case "VP8 ":
+		if n < lossyLen || !bytes.Equal(head[23:26], []byte{0x9D, 0x01, 0x2A}) {
+			return Dimensions{}, ErrNoDimensions
+		}
+
+		return Dimensions{
+			Width:  int(binary.LittleEndian.Uint16(head[26:]) & 0x3FFF),
+			Height: int(binary.LittleEndian.Uint16(head[28:]) & 0x3FFF),
+		}, nil
+	case "VP8L":
+		if head[20] != 0x2F { // lossless signature
+			return Dimensions{}, ErrNoDimensions
+		}
+
+		bits := binary.LittleEndian.Uint32(head[21:])
+
+		return Dimensions{Width: int(bits&0x3FFF) + 1, Height: int(bits>>14&0x3FFF) + 1}, nil
+	case "VP8X":
+		if n < lossyLen {
+			return Dimensions{}, ErrNoDimensions
+		}
+
+		return Dimensions{Width: int(uint24(head[24:])) + 1, Height: int(uint24(head[27:])) + 1}, nil
+	}
+
+	return Dimensions{}, ErrNoDimensions
+}
+
+func uint24(b []byte) uint32 {
+	return uint32(b[0]) | uint32(b[1])<<8 | uint32(b[2])<<16
+}
@@ -0,0 +1,146 @@
+package media_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/kpod13/journal2day1/internal/media"
+)
+
+func encodedImage(t *testing.T, encode func(*bytes.Buffer, image.Image) error, width, height int) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+
+	require.NoError(t, encode(&buf, image.NewRGBA(image.Rect(0, 0, width, height))))
+
+	return buf.Bytes()
+}
+
+func encodeJPEG(buf *bytes.Buffer, img image.Image) error { return jpeg.Encode(buf, img, nil) }
+func encodePNG(buf *bytes.Buffer, img image.Image) error  { return png.Encode(buf, img) }
+func encodeGIF(buf *bytes.Buffer, img image.Image) error  { return gif.Encode(buf, img, nil) }
+
+func bmffBox(typ string, payload ...[]byte) []byte {
+	data := bytes.Join(payload, nil)
+	out := binary.BigEndian.AppendUint32(nil, uint32(len(data)+8))
+	out = append(out, typ...)
+
+	return append(out, data...)
+}
+
+func fullBox(typ string, payload ...[]byte) []byte {
+	return bmffBox(typ, append([][]byte{{0, 0, 0, 0}}, payload...)...)
+}
+
+func heicImage(width, height uint32, rotation byte) []byte {
+	ispe := fullBox("ispe", binary.BigEndian.AppendUint32(binary.BigEndian.AppendUint32(nil, width), height))
+	thumb := fullBox("ispe", binary.BigEndian.AppendUint32(binary.BigEndian.AppendUint32(nil, 320), 240))
+	irot := bmffBox("irot", []byte{rotation})
+
+	// Item 2 is a thumbnail, item 1 the primary image with ispe 1 and irot 3.
+	ipma := fullBox("ipma",
+		binary.BigEndian.AppendUint32(nil, 2),
+		[]byte{0, 2, 1, 0x82},
+		[]byte{0, 1, 2, 0x81, 0x03},
+	)
+
+	return append(
+		bmffBox("ftyp", []byte("heic"), []byte{0, 0, 0, 0}, []byte("mif1heic")),
+		fullBox("meta",
+			fullBox("hdlr", make([]byte, 20)),
+			fullBox("pitm", []byte{0, 1}),
+			bmffBox("iprp", bmffBox("ipco", ispe, thumb, irot), ipma),
+		)...,
+	)
+}
+
+func TestImageDimensions(t *testing.T) {
+	t.Parallel()
+
+	jpegData := encodedImage(t, encodeJPEG, 6, 4)
+
+	testCases := []struct {
+		name string
+		data []byte
+		want media.Dimensions
+	}{
+		{name: "jpeg", data: jpegData, want: media.Dimensions{Width: 6, Height: 4}},
+		{name: "jpeg flipped", data: withEXIFOrientation(jpegData, 3), want: media.Dimensions{Width: 6, Height: 4}},
+		{name: "jpeg portrait", data: withEXIFOrientation(jpegData, 6), want: media.Dimensions{Width: 4, Height: 6}},
+		{name: "png", data: encodedImage(t, encodePNG, 3, 2), want: media.Dimensions{Width: 3, Height: 2}},
+		{name: "gif", data: encodedImage(t, encodeGIF, 4, 5), want: media.Dimensions{Width: 4, Height: 5}},
+		{
+			name: "webp lossy",
+			data: append([]byte("RIFF\x00\x00\x00\x00WEBPVP8 \x00\x00\x00\x00\x00\x00\x00\x9d\x01\x2a"),
+				0x80, 0x02, 0xE0, 0x01),
+			want: media.Dimensions{Width: 640, Height: 480},
+		},
+		{
+			name: "webp lossless",
+			data: append([]byte("RIFF\x00\x00\x00\x00WEBPVP8L\x00\x00\x00\x00\x2f"),
+				binary.LittleEndian.AppendUint32(nil, 99|49<<14)...),
+			want: media.Dimensions{Width: 100, Height: 50},
+		},
+		{
+			name: "webp extended",
+			data: append([]byte("RIFF\x00\x00\x00\x00WEBPVP8X\x0a\x00\x00\x00\x00\x00\x00\x00"),
+				0x7F, 0x07, 0x00, 0x37, 0x04, 0x00),
+			want: media.Dimensions{Width: 1920, Height: 1080},
+		},
+		{name: "heic", data: heicImage(4032, 3024, 0), want: media.Dimensions{Width: 4032, Height: 3024}},
+		{name: "heic portrait", data: heicImage(4032, 3024, 1), want: media.Dimensions{Width: 3024, Height: 4032}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			dims, err := media.ImageDimensions(bytes.NewReader(tc.data))
+
+			require.NoError(t, err)
+			require.Equal(t, tc.want, dims)
+		})
+	}
+}
+
+func TestImageDimensionsErrors(t *testing.T) {
+	t.Parallel()
+
+	_, err := media.ImageDimensions(bytes.NewReader([]byte("not an image at all")))
+	require.ErrorIs(t, err, media.ErrUnknownFormat)
+
+	_, err = media.ImageDimensions(bytes.NewReader(nil))
+	require.ErrorIs(t, err, media.ErrUnknownFormat)
+
+	jpegData := encodedImage(t, encodeJPEG, 6, 4)
+	_, err = media.ImageDimensions(bytes.NewReader(jpegData[:20]))
+	require.Error(t, err, "truncated before the frame header")
+
+	heic := heicImage(4032, 3024, 0)
+	_, err = media.ImageDimensions(bytes.NewReader(heic[:len(heic)-10]))
+	require.Error(t, err)
+}
+
+func TestImageFileDimensions(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "photo.png")
+	require.NoError(t, os.WriteFile(path, encodedImage(t, encodePNG, 7, 3), 0o600))
+
+	dims, err := media.ImageFileDimensions(path)
+	require.NoError(t, err)
+	require.Equal(t, media.Dimensions{Width: 7, Height: 3}, dims)
+
+	_, err = media.ImageFileDimensions(filepath.Join(t.TempDir(), "missing.png"))
+	require.Error(t, err)
+}
@@ -0,0 +1,134 @@
+package media
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"io"
+
+	"github.com/pkg/errors"
+)
+
+// JPEG markers.
+const (
+	markerPrefix = 0xFF
+	markerSOS    = 0xDA // start of scan, image data follows
+	markerEOI    = 0xD9
+	markerAPP1   = 0xE1
+)
+
+var exifHeader = []byte("Exif\x00\x00")
+
+// jpegDimensions returns the frame size of a JPEG turned by its EXIF
+// orientation.
+func jpegDimensions(r *bufio.Reader) (Dimensions, error) {
+	exif, dims, err := readJPEGHeader(r)
+	if err != nil {
+		return Dimensions{}, err
+	}
+
+	return dims.rotate(exifOrientation(exif).swapsAxes()), nil
+}
+
+// readJPEGHeader walks the JPEG segments up to the start of frame and returns
+// the EXIF data, starting with its TIFF header, and the stored frame size.
+// The EXIF data is also returned with ErrNoDimensions when no frame follows.
+func readJPEGHeader(r *bufio.Reader) (exif []byte, dims Dimensions, err error) {
+	if _, err := r.Discard(2); err != nil { // start of image
+		return nil, Dimensions{}, errors.Wrap(err, "failed to read JPEG header")
+	}
+
+	for {
+		marker, err := nextMarker(r)
+		if err != nil {
+			return exif, Dimensions{}, err
+		}
+
+		if marker == markerSOS || marker == markerEOI {
+			return exif, Dimensions{}, ErrNoDimensions
+		}
+
+		if isStandaloneMarker(marker) {
+			continue
+		}
+
+		var length uint16
+		if err := binary.Read(r, binary.BigEndian, &length); err != nil {
+			return exif, Dimensions{}, errors.Wrap(err, "failed to read JPEG segment")
+		}
+
+		if length < 2 { // the length includes its own two bytes
+			return exif, Dimensions{}, ErrNoDimensions
+		}
+
+		payload := int(length) - 2
+
+		switch {
+		case isStartOfFrame(marker):
+			dims, err := readFrameDimensions(r)
+
+			return exif, dims, err
+		case marker == markerAPP1 && exif == nil:
+			data := make([]byte, payload)
+			if _, err := io.ReadFull(r, data); err != nil {
+				return nil, Dimensions{}, errors.Wrap(err, "failed to read JPEG segment")
+			}
+
+			if rest, ok := bytes.CutPrefix(data, exifHeader); ok { // not XMP, which also uses APP1
+				exif = rest
+			}
+		default:
+			if _, err := r.Discard(payload); err != nil {
+				return exif, Dimensions{}, errors.Wrap(err, "failed to read JPEG segment")
+			}
+		}
+	}
+}
+
+// nextMarker reads the next marker, skipping fill bytes.
+func nextMarker(r *bufio.Reader) (byte, error) {
+	b, err := r.ReadByte()
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to read JPEG marker")
+	}
+
+	if b != markerPrefix {
+		return 0, ErrNoDimensions
+	}
+
+	for b == markerPrefix {
+		if b, err = r.ReadByte(); err != nil {
+			return 0, errors.Wrap(err, "failed to read JPEG marker")
+		}
+	}
+
+	return b, nil
+}
+
+func isStandaloneMarker(marker byte) bool {
+	return marker == 0x01 || (marker >= 0xD0 && marker <= 0xD7)
+}
+
+// isStartOfFrame reports whether the marker starts a frame. DHT (C4), JPG
+// (C8) and DAC (CC) share the range but are not frames.
+func isStartOfFrame(marker byte) bool {
+	return marker >= 0xC0 && marker <= 0xCF && marker != 0xC4 && marker != 0xC8 && marker != 0xCC
+}
+
+func readFrameDimensions(r io.Reader) (Dimensions, error) {
+	frame := make([]byte, 5) // precision, height and width
+	if _, err := io.ReadFull(r, frame); err != nil {
+		return Dimensions{}, errors.Wrap(err, "failed to read JPEG frame")
+	}
+
+	dims := Dimensions{
+		Width:  int(binary.BigEndian.Uint16(frame[3:])),
+		Height: int(binary.BigEndian.Uint16(frame[1:])),
+	}
+
+	if dims.Width == 0 || dims.Height == 0 {
+		return Dimensions{}, ErrNoDimensions
+	}
+
+	return dims, nil
+}
@@ -0,0 +1,153 @@
+package media
+
+import "bufio"
+
+// Lengths of audio frame headers.
+const (
+	mpegHeaderLen = 4
+	adtsHeaderLen = 7
+)
+
+// adtsSampleRates are the sample rates of ADTS AAC frames by index.
+var adtsSampleRates = []int{96000, 88200, 64000, 48000, 44100, 32000, 24000, 22050, 16000, 12000, 11025, 8000, 7350}
+
+// MPEG audio versions and layers as coded in a frame header.
+const (
+	mpegVersion1  = 3
+	mpegVersion2  = 2
+	mpegVersion25 = 0
+	mpegLayer1    = 3
+	mpegLayer3    = 1
+)
+
+// mpegBitrates are the bitrates in kbit/s by version (1 or 2 and 2.5), layer
+// (I, II or III) and index.
+var mpegBitrates = [2][3][15]int{
+	{
+		{0, 32, 64, 96, 128, 160, 192, 224, 256, 288, 320, 352, 384, 416, 448},
+		{0, 32, 48, 56, 64, 80, 96, 112, 128, 160, 192, 224, 256, 320, 384},
+		{0, 32, 40, 48, 56, 64, 80, 96, 112, 128, 160, 192, 224, 256, 320},
+	},
+	{
+		{0, 32, 48, 56, 64, 80, 96, 112, 128, 144, 160, 176, 192, 224, 256},
+		{0, 8, 16, 24, 32, 40, 48, 56, 64, 80, 96, 112, 128, 144, 160},
+		{0, 8, 16, 24, 32, 40, 48, 56, 64, 80, 96, 112, 128, 144, 160},
+	},
+}
+
+// mpegSampleRates are the sample rates of MPEG 1 audio; MPEG 2 halves and
+// MPEG 2.5 quarters them.
+var mpegSampleRates = []int{44100, 48000, 32000}
+
+// adtsFrame parses the header of an ADTS AAC frame. Each raw data block
+// holds 1024 samples.
+func adtsFrame(header []byte) (audioFrame, bool) {
+	const samplesPerBlock = 1024
+
+	if len(header) < adtsHeaderLen {
+		return audioFrame{}, false
+	}
+
+	rateIndex := int(header[2]>>2) & 0x0F
+	length := int(header[3]&0x03)<<11 | int(header[4])<<3 | int(header[5]>>5)
+	blocks := int(header[6]&0x03) + 1
+
+	if rateIndex >= len(adtsSampleRates) || length < adtsHeaderLen {
+		return audioFrame{}, false
+	}
+
+	return audioFrame{length: length, samples: blocks * samplesPerBlock, sampleRate: adtsSampleRates[rateIndex]}, true
+}
+
+// mpegFrame parses the header of an MPEG 1, 2 or 2.5 layer I, II or III
+// frame. Free format bitrates are not supported.
+func mpegFrame(header []byte) (audioFrame, bool) {
+	if header[1]&0xE0 != 0xE0 {
+		return audioFrame{}, false
+	}
+
+	version := int(header[1]>>3) & 0x03
+	layer := int(header[1]>>1) & 0x03
+	bitrateIndex := int(header[2] >> 4)
+	rateIndex := int(header[2]>>2) & 0x03
+	padding := int(header[2]>>1) & 0x01
+
+	if version == 1 || layer == 0 || bitrateIndex == 0 || bitrateIndex == 15 || rateIndex == 3 {
+		return audioFrame{}, false
+	}
+
+	table := 1
+	if version == mpegVersion1 {
+		table = 0
+	}
+
+	bitrate := mpegBitrates[table][mpegLayer1-layer][bitrateIndex] * 1000
+	sampleRate := mpegSampleRates[rateIndex]
+
+	switch version {
+	case mpegVersion2:
+		sampleRate /= 2
+	case mpegVersion25:
+		sampleRate /= 4
+	}
+
+	if layer == mpegLayer1 {
+		const slotLen = 4
+
+		length := (12*bitrate/sampleRate + padding) * slotLen
+
+		return audioFrame{length: length, samples: 384, sampleRate: sampleRate}, true
+	}
+
+	frame := audioFrame{samples: 1152, sampleRate: sampleRate}
+	if layer == mpegLayer3 && version != mpegVersion1 {
+		frame.samples = 576
+	}
+
+	frame.length = frame.samples/8*bitrate/sampleRate + padding
+
+	return frame, frame.length > mpegHeaderLen
+}
+
+// xingFrames returns the frame count from the Xing or Info header in the
+// first frame of a variable bitrate MP3, which follows the side information.
+func xingFrames(r *bufio.Reader, header []byte) (int, bool) {
+	const (
+		framesFlag = 0x01
+		monoMode   = 3
+	)
+
+	if header[1]&0xE0 != 0xE0 || int(header[1]>>1)&0x03 != mpegLayer3 {
+		return 0, false
+	}
+
+	mono := int(header[3]>>6) == monoMode
+	version1 := int(header[1]>>3)&0x03 == mpegVersion1
+
+	var sideInfoLen int
+
+	switch {
+	case version1 && !mono:
+		sideInfoLen = 32
+	case version1 || !mono:
+		sideInfoLen = 17
+	default:
+		sideInfoLen = 9
+	}
+
+	offset := mpegHeaderLen + sideInfoLen
+
+	data, err := r.Peek(offset + 12)
+	if err != nil {
+		return 0, false
+	}
+
+	tag := string(data[offset : offset+4])
+	if (tag != "Xing" && tag != "Info") || data[offset+7]&framesFlag == 0 {
+		return 0, false
+	}
+
+	frames := int(data[offset+8])<<24 | int(data[offset+9])<<16 | int(data[offset+10])<<8 | int(data[offset+11])
+
+	return frames, frames > 0
+}
@@ -0,0 +1,157 @@
+package media
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// maxMovieSize bounds the movie (moov) box read into memory. It holds the
+// sample tables of all tracks, which grow with the length of the video.
+const maxMovieSize = 64 << 20
+
+// VideoInfo is the duration and displayed size of a video.
+type VideoInfo struct {
+	Duration time.Duration
+	Dimensions
+}
+
+// VideoFileMetadata returns the metadata of the MOV, MP4 or M4V video at path.
+func VideoFileMetadata(path string) (VideoInfo, error) {
+	f, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return VideoInfo{}, errors.Wrap(err, "failed to open video")
+	}
+
+	defer func() { _ = f.Close() }() //nolint:errcheck // read-only file close errors are not critical
+
+	return VideoMetadata(f)
+}
+
+// VideoMetadata reads the duration from the movie header (mvhd) of a
+// QuickTime or MP4 file, or from the movie extends header (mehd) of a
+// fragmented MP4, and the size from the header (tkhd) of the first video
+// track. Only the movie box is read into memory; other boxes are skipped,
+// by seeking when r is an io.Seeker.
+func VideoMetadata(r io.Reader) (VideoInfo, error) {
+	moov, err := readTopLevelBox(r, "moov", maxMovieSize)
+	if err != nil {
+		return VideoInfo{}, errors.Wrap(err, "failed to read movie header")
+	}
+
+	boxes, err := parseBoxes(moov)
+	if err != nil {
+		return VideoInfo{}, err
+	}
+
+	mvhd, ok := findBox(boxes, "mvhd")
+	if !ok {
+		return VideoInfo{}, errors.Wrap(errInvalidBox, "no mvhd box")
+	}
+
+	timescale, duration := movieDuration(mvhd.data)
+
+	if duration == 0 {
+		duration = fragmentDuration(boxes)
+	}
+
+	var info VideoInfo
+
+	if timescale > 0 {
+		info.Duration = time.Duration(float64(duration) / float64(timescale) * float64(time.Second))
+	}
+
+	for _, trak := range boxes {
+		if trak.typ != "trak" {
+			continue
+		}
+
+		if dims, ok := trackDimensions(trak.data); ok {
+			info.Dimensions = dims
+
+			break
+		}
+	}
+
+	return info, nil
+}
+
+// movieDuration returns the timescale and the duration in timescale units
+// from a movie header.
+func movieDuration(mvhd []byte) (timescale uint32, duration uint64) {
+	c := newCursor(mvhd)
+	version := c.u8()
+	c.take(3)            // flags
+	c.versioned(version) // creation time
+	c.versioned(version) // modification time
+	timescale = c.u32()
+	duration = c.versioned(version)
+
+	if !c.ok || duration == 1<<32-1 || duration == 1<<64-1 { // all ones: unknown
+		return timescale, 0
+	}
+
+	return timescale, duration
+}
+
+// fragmentDuration returns the duration of a fragmented MP4 from its movie
+// extends header, in movie timescale units.
+func fragmentDuration(moov []box) uint64 {
+	mvex, err := childBoxes(moov, "mvex")
+	if err != nil {
+		return 0
+	}
+
+	mehd, ok := findBox(mvex, "mehd")
+	if !ok {
+		return 0
+	}
+
+	c := newCursor(mehd.data)
+	version := c.u8()
+	c.take(3) // flags
+
+	if duration := c.versioned(version); c.ok {
+		return duration
+	}
+
+	return 0
+}
+
+// trackDimensions returns the displayed size from a track header. Audio and
+// other tracks without a size report false. Rotation matrices that turn the
+// track by 90 or 270 degrees swap width and height, as for portrait videos.
+func trackDimensions(trak []byte) (Dimensions, bool) {
+	boxes, err := parseBoxes(trak)
+	if err != nil {
+		return Dimensions{}, false
+	}
+
+	tkhd, ok := findBox(boxes, "tkhd")
+	if !ok {
+		return Dimensions{}, false
+	}
+
+	c := newCursor(tkhd.data)
+	version := c.u8()
+	c.take(3)            // flags
+	c.versioned(version) // creation time
+	c.versioned(version) // modification time
+	c.take(8)            // track ID and reserved
+	c.versioned(version) // duration
+	c.take(16)           // reserved, layer, alternate group, volume and reserved
+
+	a, b := int32(c.u32()), int32(c.u32()) //nolint:gosec // matrix entries are signed fixed-point numbers
+	c.take(28)                             // rest of the matrix
+
+	dims := Dimensions{Width: int(c.u32() >> 16), Height: int(c.u32() >> 16)} // 16.16 fixed point
+
+	if !c.ok || dims.Width == 0 || dims.Height == 0 {
+		return Dimensions{}, false
+	}
+
+	return dims.rotate(a == 0 && b != 0), true
+}
@@ -0,0 +1,110 @@
+package media_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/kpod13/journal2day1/internal/media"
+)
+
+func be32(values ...uint32) []byte {
+	var out []byte
+
+	for _, v := range values {
+		out = binary.BigEndian.AppendUint32(out, v)
+	}
+
+	return out
+}
+
+func mvhd(timescale, duration uint32) []byte {
+	return fullBox("mvhd", be32(0, 0, timescale, duration), make([]byte, 80))
+}
+
+// tkhd builds a version 0 track header. A rotated track turns by 90 degrees.
+func tkhd(width, height uint32, rotated bool) []byte {
+	matrix := be32(0x10000, 0, 0, 0, 0x10000, 0, 0, 0, 0x40000000)
+	if rotated {
+		matrix = be32(0, 0x10000, 0, 0xFFFF0000, 0, 0, 0, 0, 0x40000000)
+	}
+
+	return fullBox("tkhd", be32(0, 0, 1, 0, 0), make([]byte, 16), matrix, be32(width<<16, height<<16))
+}
+
+func movie(boxes ...[]byte) []byte {
+	return append(bmffBox("ftyp", []byte("qt  "), be32(0)), bmffBox("moov", boxes...)...)
+}
+
+func TestVideoMetadata(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name string
+		data []byte
+		want media.VideoInfo
+	}{
+		{
+			name: "landscape",
+			data: movie(mvhd(600, 6300), bmffBox("trak", tkhd(0, 0, false)), bmffBox("trak", tkhd(1920, 1080, false))),
+			want: media.VideoInfo{
+				Duration:   10500 * time.Millisecond,
+				Dimensions: media.Dimensions{Width: 1920, Height: 1080},
+			},
+		},
+		{
+			name: "portrait",
+			data: movie(mvhd(1000, 3000), bmffBox("trak", tkhd(1920, 1080, true))),
+			want: media.VideoInfo{Duration: 3 * time.Second, Dimensions: media.Dimensions{Width: 1080, Height: 1920}},
+		},
+		{
+			name: "fragmented",
+			data: movie(
+				mvhd(90000, 0),
+				bmffBox("trak", tkhd(640, 480, false)),
+				bmffBox("mvex", bmffBox("mehd", []byte{1, 0, 0, 0}, binary.BigEndian.AppendUint64(nil, 450000))),
+			),
+			want: media.VideoInfo{Duration: 5 * time.Second, Dimensions: media.Dimensions{Width: 640, Height: 480}},
+		},
+		{
+			name: "media data first",
+			data: append(
+				append(bmffBox("ftyp", []byte("isom"), be32(0)), bmffBox("mdat", make([]byte, 1<<20))...),
+				bmffBox("moov", mvhd(600, 600), bmffBox("trak", tkhd(1280, 720, false)))...,
+			),
+			want: media.VideoInfo{Duration: time.Second, Dimensions: media.Dimensions{Width: 1280, Height: 720}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			info, err := media.VideoMetadata(bytes.NewReader(tc.data))
+			require.NoError(t, err)
+			require.Equal(t, tc.want, info)
+
+			// Readers that cannot seek skip boxes by reading them.
+			info, err = media.VideoMetadata(io.MultiReader(bytes.NewReader(tc.data)))
+			require.NoError(t, err)
+			require.Equal(t, tc.want, info)
+		})
+	}
+}
+
+func TestVideoMetadataErrors(t *testing.T) {
+	t.Parallel()
+
+	_, err := media.VideoMetadata(bytes.NewReader(bmffBox("ftyp", []byte("isom"))))
+	require.Error(t, err, "no movie box")
+
+	_, err = media.VideoMetadata(bytes.NewReader(bmffBox("moov", bmffBox("trak"))))
+	require.Error(t, err, "no movie header")
+
+	_, err = media.VideoFileMetadata("/nonexistent/video.mov")
+	require.Error(t, err)
+}
@@ -4,12 +4,16 @@ package models
 import "time"
 
 // AppleJournalEntry represents a parsed entry from Apple Journal HTML export.
+// Body blocks and asset positions together preserve the document order of the
+// export's bodyText blocks and asset grids.
 type AppleJournalEntry struct {
-	Date     time.Time
-	Title    string
-	Body     string
-	Assets   []AppleJournalAsset
-	FilePath string
+	Date        time.Time
+	Title       string
+	Body        string // Markdown rendering of Blocks
+	Blocks      []TextBlock
+	Assets      []AppleJournalAsset
+	FilePath    string
+	ContentHash string // hex SHA-256 of the entry's HTML file
 }
 
 // AppleJournalAsset represents a media asset in an Apple Journal entry.
@@ -18,12 +22,120 @@ type AppleJournalAsset struct {
 	Type      string
 	FilePath  string
 	Extension string
+	Position  int             // index of the body block the asset precedes
+	Place     *MapPlace       // place shown by a map asset
+	Activity  *MotionActivity // activity recorded by a motion activity asset
+	Mood      *StateOfMind    // mood or emotion logged by a State of Mind asset
+	Motion    *MotionVideo    // motion video of a Live Photo, whose still is FilePath
+}
+
+// MotionVideo is the video a Live Photo records around its still image.
+type MotionVideo struct {
+	FilePath  string
+	Extension string
+}
+
+// MotionActivity is a workout or walk recorded by a motion activity asset.
+// Zero fields are unknown.
+type MotionActivity struct {
+	Type     string // e.g. "walk", "run" or "cycling"
+	Label    string // caption shown in Apple Journal
+	Steps    int
+	Distance float64 // meters
+	Duration time.Duration
+}
+
+// MapPlace is the place a map asset points at.
+type MapPlace struct {
+	Label     string
+	Latitude  *float64
+	Longitude *float64
+	Radius    float64 // meters, 0 when unknown
+}
+
+// HasCoordinates reports whether the place has a latitude and longitude.
+func (p *MapPlace) HasCoordinates() bool {
+	return p.Latitude != nil && p.Longitude != nil
+}
+
+// BlockKind identifies the paragraph-level role of a body text block.
+type BlockKind int
+
+// Body text block kinds.
+const (
+	BlockParagraph BlockKind = iota
+	BlockHeading
+	BlockListItem
+)
+
+// TextBlock is a paragraph, heading or list item of an entry body.
+type TextBlock struct {
+	Kind    BlockKind
+	Level   int  // heading level, 1-6
+	Ordered bool // numbered list item
+	Depth   int  // list nesting depth, 0 for top level
+	Index   int  // position of a numbered item within its list
+	Quote   bool
+	Runs    []TextRun
+}
+
+// TextRun is a piece of text sharing a single inline style.
+type TextRun struct {
+	Text  string
+	Style TextStyle
+}
+
+// TextStyle is the character formatting applied to a run of text.
+type TextStyle struct {
+	Bold          bool
+	Italic        bool
+	Underline     bool
+	Strikethrough bool
+	Link          string
+}
+
+// StateOfMind is a mood or emotion logged with a State of Mind asset. Names
+// are lower case with dashes, e.g. "slightly-pleasant".
+type StateOfMind struct {
+	Kind           string   // "momentary-emotion" or "daily-mood"
+	Valence        *float64 // from -1, very unpleasant, to 1, very pleasant
+	Classification string   // valence class, e.g. "very-unpleasant" or "neutral"
+	Labels         []string // feelings, e.g. "happy" or "grateful"
+	Associations   []string // what the mood relates to, e.g. "family" or "work"
 }
 
 // AppleJournalResourceMeta represents the JSON metadata for a resource.
+// Location fields are present only for assets with a known place, activity
+// and mood fields only for motion activity and State of Mind assets.
 type AppleJournalResourceMeta struct {
-	Date      float64 `json:"date"`
-	PlaceName string  `json:"placeName"`
+	Date               float64  `json:"date"` // Cocoa timestamp
+	PlaceName          string   `json:"placeName"`
+	Locality           string   `json:"locality"`
+	AdministrativeArea string   `json:"administrativeArea"`
+	Country            string   `json:"country"`
+	Latitude           *float64 `json:"latitude"`
+	Longitude          *float64 `json:"longitude"`
+	TimeZone           string   `json:"timeZone"` // IANA name, e.g. "Europe/Sofia"
+	Radius             float64  `json:"radius"`   // meters, for map assets
+	ActivityType       string   `json:"activityType"`
+	Steps              int      `json:"steps"`
+	Distance           float64  `json:"distance"` // meters
+	Duration           float64  `json:"duration"` // seconds
+	StateOfMindKind    string   `json:"kind"`
+	Valence            *float64 `json:"valence"`
+	Classification     string   `json:"valenceClassification"`
+	Labels             []string `json:"labels"`
+	Associations       []string `json:"associations"`
+}
+
+// HasCoordinates reports whether the resource has a latitude and longitude.
+func (m *AppleJournalResourceMeta) HasCoordinates() bool {
+	return m.Latitude != nil && m.Longitude != nil
+}
+
+// HasLocation reports whether the resource has coordinates or a place name.
+func (m *AppleJournalResourceMeta) HasLocation() bool {
+	return m.HasCoordinates() || m.PlaceName != "" || m.Locality != "" || m.Country != ""
 }
 
 // appleCocoaEpoch is the reference date for Apple/Cocoa timestamps (2001-01-01).
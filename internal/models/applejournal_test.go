@@ -39,3 +39,36 @@ func TestCocoaTimestampToTime(t *testing.T) {
 		})
 	}
 }
+
+func TestAppleJournalResourceMetaLocation(t *testing.T) {
+	t.Parallel()
+
+	latitude, longitude := 41.89, 12.49
+
+	tests := []struct {
+		name           string
+		meta           models.AppleJournalResourceMeta
+		hasCoordinates bool
+		hasLocation    bool
+	}{
+		{name: "empty", meta: models.AppleJournalResourceMeta{Date: 736500000}},
+		{
+			name:           "coordinates",
+			meta:           models.AppleJournalResourceMeta{Latitude: &latitude, Longitude: &longitude},
+			hasCoordinates: true,
+			hasLocation:    true,
+		},
+		{name: "latitude only", meta: models.AppleJournalResourceMeta{Latitude: &latitude}},
+		{name: "place name", meta: models.AppleJournalResourceMeta{PlaceName: "Colosseum"}, hasLocation: true},
+		{name: "country", meta: models.AppleJournalResourceMeta{Country: "Italy"}, hasLocation: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tt.hasCoordinates, tt.meta.HasCoordinates())
+			require.Equal(t, tt.hasLocation, tt.meta.HasLocation())
+		})
+	}
+}
@@ -24,8 +24,10 @@ type DayOneEntry struct {
 	Duration       int             `json:"duration"`
 	TimeZone       string          `json:"timeZone"`
 	CreationDevice string          `json:"creationDevice,omitempty"`
+	Tags           []string        `json:"tags,omitempty"`
 	Photos         []DayOnePhoto   `json:"photos,omitempty"`
 	Videos         []DayOneVideo   `json:"videos,omitempty"`
+	Audios         []DayOneAudio   `json:"audios,omitempty"`
 	Location       *DayOneLocation `json:"location,omitempty"`
 }
 
@@ -61,6 +63,19 @@ type DayOneVideo struct {
 	Height         int    `json:"height,omitempty"`
 }
 
+// DayOneAudio represents an audio recording attachment in DayOne.
+type DayOneAudio struct {
+	Identifier     string  `json:"identifier"` // UUID without dashes, uppercase
+	Format         string  `json:"format"`     // m4a, aac, mp3 or wav
+	MD5            string  `json:"md5"`
+	FileSize       int64   `json:"fileSize"`
+	OrderInEntry   int     `json:"orderInEntry"`
+	CreationDevice string  `json:"creationDevice,omitempty"`
+	Duration       float64 `json:"duration"` // seconds
+	Favorite       bool    `json:"favorite"`
+	Date           string  `json:"date"` // ISO 8601 format
+}
+
 // DayOneLocation represents location information for an entry.
 type DayOneLocation struct {
 	PlaceName          string        `json:"placeName,omitempty"`
@@ -90,3 +105,47 @@ type DayOneCenter struct {
 	Longitude float64 `json:"longitude"`
 	Latitude  float64 `json:"latitude"`
 }
+
+// DayOneRichText is the structured document serialized into DayOneEntry.RichText.
+type DayOneRichText struct {
+	Contents []DayOneRichTextContent `json:"contents"`
+	Meta     DayOneRichTextMeta      `json:"meta"`
+}
+
+// DayOneRichTextContent is either a run of text or a group of embedded objects.
+type DayOneRichTextContent struct {
+	Text            string                    `json:"text,omitempty"`
+	Attributes      *DayOneRichTextAttributes `json:"attributes,omitempty"`
+	EmbeddedObjects []DayOneEmbeddedObject    `json:"embeddedObjects,omitempty"`
+}
+
+// DayOneRichTextAttributes describes the formatting of a run of text.
+type DayOneRichTextAttributes struct {
+	Bold          bool                  `json:"bold,omitempty"`
+	Italic        bool                  `json:"italic,omitempty"`
+	Underline     bool                  `json:"underline,omitempty"`
+	Strikethrough bool                  `json:"strikethrough,omitempty"`
+	LinkURL       string                `json:"linkURL,omitempty"`
+	Line          *DayOneLineAttributes `json:"line,omitempty"`
+}
+
+// DayOneLineAttributes describes the paragraph a run of text belongs to.
+type DayOneLineAttributes struct {
+	Header      int    `json:"header,omitempty"`
+	ListStyle   string `json:"listStyle,omitempty"` // bulleted or numbered
+	IndentLevel int    `json:"indentLevel,omitempty"`
+	ListIndex   int    `json:"listIndex,omitempty"`
+	Quote       bool   `json:"quote,omitempty"`
+}
+
+// DayOneEmbeddedObject references an attachment placed inside the rich text.
+type DayOneEmbeddedObject struct {
+	Type       string `json:"type"`       // photo, video or audio
+	Identifier string `json:"identifier"` // matches the attachment identifier
+}
+
+// DayOneRichTextMeta contains rich text document metadata.
+type DayOneRichTextMeta struct {
+	Version           int  `json:"version"`
+	SmallLinesRemoved bool `json:"small-lines-removed"`
+}
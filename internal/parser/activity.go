@@ -0,0 +1,110 @@
+package parser
+
+import (
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"golang.org/x/net/html"
+
+	"github.com/kpod13/journal2day1/internal/models"
+)
+
+// motionActivity extracts the activity of a motion activity grid item from
+// its caption and data attributes; the resource sidecar, when present, wins.
+// It returns nil when neither describes the activity.
+func (p *AppleJournalParser) motionActivity(n *html.Node, id string) *models.MotionActivity {
+	activity := &models.MotionActivity{
+		Type:  slug(findAttr(n, "data-activity-type")),
+		Label: strings.Join(strings.Fields(getTextContent(n)), " "),
+	}
+
+	activity.Steps, _ = strconv.Atoi(findAttr(n, "data-steps"))
+	activity.Distance, _ = strconv.ParseFloat(findAttr(n, "data-distance"), 64)
+
+	if seconds, err := strconv.ParseFloat(findAttr(n, "data-duration"), 64); err == nil {
+		activity.Duration = secondsDuration(seconds)
+	}
+
+	if meta, err := p.LoadResourceMeta(id); err == nil {
+		applySidecarActivity(activity, meta)
+	}
+
+	if *activity == (models.MotionActivity{}) {
+		return nil
+	}
+
+	return activity
+}
+
+// applySidecarActivity overrides the activity with the measurements from its
+// resource sidecar.
+func applySidecarActivity(activity *models.MotionActivity, meta *models.AppleJournalResourceMeta) {
+	if meta.ActivityType != "" {
+		activity.Type = slug(meta.ActivityType)
+	}
+
+	if meta.Steps > 0 {
+		activity.Steps = meta.Steps
+	}
+
+	if meta.Distance > 0 {
+		activity.Distance = meta.Distance
+	}
+
+	if meta.Duration > 0 {
+		activity.Duration = secondsDuration(meta.Duration)
+	}
+}
+
+// slug normalizes a name for use in tags, e.g. "Outdoor Walk" or
+// "outdoorWalk" to "outdoor-walk".
+func slug(name string) string {
+	var words strings.Builder
+
+	previous := ' '
+
+	for _, r := range strings.TrimSpace(name) {
+		switch {
+		case r == '_' || r == '-' || unicode.IsSpace(r):
+			r = '-'
+		case unicode.IsUpper(r) && unicode.IsLower(previous):
+			words.WriteRune('-')
+		}
+
+		if r != '-' || previous != '-' {
+			words.WriteRune(unicode.ToLower(r))
+		}
+
+		previous = r
+	}
+
+	return strings.Trim(words.String(), "-")
+}
+
+func secondsDuration(seconds float64) time.Duration {
+	if seconds <= 0 {
+		return 0
+	}
+
+	return time.Duration(seconds * float64(time.Second))
+}
+
+// findAttr returns the first non-empty value of an attribute on the node or
+// its descendants.
+func findAttr(n *html.Node, key string) string {
+	if n.Type == html.ElementNode {
+		if value := getAttr(n, key); value != "" {
+			return value
+		}
+	}
+
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if value := findAttr(c, key); value != "" {
+			return value
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,77 @@
+package parser_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/kpod13/journal2day1/internal/models"
+	"github.com/kpod13/journal2day1/internal/parser"
+)
+
+func TestParseEntryMotionActivity(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		item    string
+		sidecar string
+		want    *models.MotionActivity
+	}{
+		{
+			name: "caption and data attributes",
+			item: `<div class="activity" data-activity-type="Outdoor Walk" data-steps="5432"
+data-distance="3200" data-duration="2700">Morning walk</div>`,
+			want: &models.MotionActivity{
+				Type:     "outdoor-walk",
+				Label:    "Morning walk",
+				Steps:    5432,
+				Distance: 3200,
+				Duration: 45 * time.Minute,
+			},
+		},
+		{
+			name:    "sidecar wins",
+			item:    `<div data-activity-type="walk" data-steps="10"></div>`,
+			sidecar: `{"activityType": "running", "steps": 8000, "distance": 10500.5, "duration": 3600}`,
+			want: &models.MotionActivity{
+				Type:     "running",
+				Steps:    8000,
+				Distance: 10500.5,
+				Duration: time.Hour,
+			},
+		},
+		{
+			name: "no data",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			tmpDir := t.TempDir()
+			createDirs(t, tmpDir)
+
+			content := `<div class="pageHeader">Monday, 15 December 2025</div>
+<div class="assetGrid"><div id="ACTIVITY-UUID" class="gridItem assetType_motionActivity">` + tt.item + `</div></div>`
+
+			entryPath := filepath.Join(tmpDir, "Entries", "2025-12-15_Walk.html")
+			require.NoError(t, os.WriteFile(entryPath, []byte(content), 0o600))
+
+			if tt.sidecar != "" {
+				sidecarPath := filepath.Join(tmpDir, "Resources", "ACTIVITY-UUID.json")
+				require.NoError(t, os.WriteFile(sidecarPath, []byte(tt.sidecar), 0o600))
+			}
+
+			entry, err := parser.NewAppleJournalParser(tmpDir).ParseEntry(entryPath)
+			require.NoError(t, err)
+			require.Len(t, entry.Assets, 1)
+			require.Equal(t, "activity", entry.Assets[0].Type)
+			require.Equal(t, tt.want, entry.Assets[0].Activity)
+		})
+	}
+}
@@ -2,53 +2,178 @@
 package parser
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"io"
+	"io/fs"
 	"os"
+	"path"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/pkg/errors"
 	"golang.org/x/net/html"
 
 	"github.com/kpod13/journal2day1/internal/models"
+	"github.com/kpod13/journal2day1/internal/workpool"
 )
 
+// Directories of an Apple Journal export.
+const (
+	entriesDir   = "Entries"
+	resourcesDir = "Resources"
+)
+
+// EntryError records an entry that could not be parsed.
+type EntryError struct {
+	Path string
+	Err  error
+}
+
+// Error implements the error interface.
+func (e *EntryError) Error() string {
+	return e.Path + ": " + e.Err.Error()
+}
+
+// Unwrap returns the cause of the failure.
+func (e *EntryError) Unwrap() error {
+	return e.Err
+}
+
 // AppleJournalParser parses Apple Journal HTML exports.
 type AppleJournalParser struct {
-	basePath string
+	fsys     fs.FS
+	basePath string    // export directory on disk, empty for other file systems
+	closer   io.Closer // releases an opened archive
+	locale   *Locale
+	jobs     int
+	lenient  bool
+	failures []EntryError
+
+	catalogOnce sync.Once
+	catalog     *ResourceCatalog
+	catalogErr  error
 }
 
 // NewAppleJournalParser creates a new parser for the given export directory.
 func NewAppleJournalParser(basePath string) *AppleJournalParser {
-	return &AppleJournalParser{basePath: basePath}
+	return &AppleJournalParser{fsys: os.DirFS(basePath), basePath: basePath}
+}
+
+// NewAppleJournalParserFS creates a new parser for an export whose Entries
+// and Resources directories are at the root of fsys.
+func NewAppleJournalParserFS(fsys fs.FS) *AppleJournalParser {
+	return &AppleJournalParser{fsys: fsys}
+}
+
+// Close releases the archive the parser was opened from, if any.
+func (p *AppleJournalParser) Close() error {
+	if p.closer == nil {
+		return nil
+	}
+
+	return p.closer.Close()
+}
+
+// SetLocale fixes the locale used for page header dates. By default the
+// locale is detected from the headers of the whole export.
+func (p *AppleJournalParser) SetLocale(name string) error {
+	locale, err := LookupLocale(name)
+	if err != nil {
+		return err
+	}
+
+	p.locale = locale
+
+	return nil
+}
+
+// SetJobs sets how many entries ParseAll parses concurrently. Values below
+// one parse sequentially.
+func (p *AppleJournalParser) SetJobs(jobs int) {
+	p.jobs = jobs
+}
+
+// SetLenient makes ParseAll skip entries that fail to parse instead of
+// returning the first error. Skipped entries are listed by Failures.
+func (p *AppleJournalParser) SetLenient(lenient bool) {
+	p.lenient = lenient
+}
+
+// Failures returns the entries skipped by the last lenient ParseAll, in file
+// name order.
+func (p *AppleJournalParser) Failures() []EntryError {
+	return p.failures
+}
+
+// Resources returns the catalog of the export's Resources directory. The
+// directory is read on first use and the catalog is reused afterwards.
+func (p *AppleJournalParser) Resources() (*ResourceCatalog, error) {
+	p.catalogOnce.Do(func() {
+		p.catalog, p.catalogErr = newResourceCatalog(p.fsys)
+	})
+
+	return p.catalog, p.catalogErr
 }
 
 // ParseAll parses all entries from the Apple Journal export directory.
+// Entries are returned in file name order regardless of the number of jobs.
 func (p *AppleJournalParser) ParseAll() ([]models.AppleJournalEntry, error) {
-	entriesDir := filepath.Join(p.basePath, "Entries")
-
-	files, err := os.ReadDir(entriesDir)
+	files, err := fs.ReadDir(p.fsys, entriesDir)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to read entries directory")
 	}
 
-	entries := make([]models.AppleJournalEntry, 0, len(files))
+	names := make([]string, 0, len(files))
 
 	for _, file := range files {
-		if file.IsDir() || !strings.HasSuffix(file.Name(), ".html") {
-			continue
+		if !file.IsDir() && strings.HasSuffix(file.Name(), ".html") {
+			names = append(names, file.Name())
 		}
+	}
+
+	parsed := make([]*models.AppleJournalEntry, len(names))
+	headers := make([]string, len(names))
+	errs := make([]error, len(names))
+
+	workpool.ForEach(p.jobs, len(names), func(i int) {
+		parsed[i], headers[i], errs[i] = p.parseEntry(path.Join(entriesDir, names[i]))
+	})
 
-		entryPath := filepath.Join(entriesDir, file.Name())
+	entries := make([]models.AppleJournalEntry, 0, len(names))
+	entryHeaders := make([]string, 0, len(names))
+	p.failures = nil
+
+	for i, err := range errs {
+		if err == nil {
+			entries = append(entries, *parsed[i])
+			entryHeaders = append(entryHeaders, headers[i])
+
+			continue
+		}
 
-		entry, err := p.ParseEntry(entryPath)
-		if err != nil {
-			return nil, errors.Wrapf(err, "failed to parse entry %s", file.Name())
+		if !p.lenient {
+			return nil, errors.Wrapf(err, "failed to parse entry %s", names[i])
 		}
 
-		entries = append(entries, *entry)
+		p.failures = append(p.failures, EntryError{
+			Path: p.displayPath(path.Join(entriesDir, names[i])),
+			Err:  err,
+		})
+	}
+
+	locale := p.locale
+	if locale == nil {
+		locale = detectLocale(entryHeaders)
+	}
+
+	for i := range entries {
+		p.resolveDate(&entries[i], entryHeaders[i], locale)
 	}
 
 	return entries, nil
@@ -56,30 +181,76 @@ func (p *AppleJournalParser) ParseAll() ([]models.AppleJournalEntry, error) {
 
 // ParseEntry parses a single Apple Journal HTML entry.
 func (p *AppleJournalParser) ParseEntry(filePath string) (*models.AppleJournalEntry, error) {
-	file, err := os.Open(filepath.Clean(filePath))
+	entry, header, err := p.parseEntry(p.fsPath(filePath))
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to open file")
+		return nil, err
+	}
+
+	locale := p.locale
+	if locale == nil {
+		locale = detectLocale([]string{header})
+	}
+
+	p.resolveDate(entry, header, locale)
+
+	return entry, nil
+}
+
+// entryBuilder collects the parts of a single entry while walking its DOM.
+type entryBuilder struct {
+	entry  *models.AppleJournalEntry
+	sheet  stylesheet
+	header string
+}
+
+// parseEntry parses an entry without resolving its date and returns the
+// page header text alongside it. The name is a path within the export.
+func (p *AppleJournalParser) parseEntry(name string) (*models.AppleJournalEntry, string, error) {
+	file, err := p.fsys.Open(name)
+	if err != nil {
+		return nil, "", errors.Wrap(err, "failed to open file")
 	}
 
 	defer func() { _ = file.Close() }() //nolint:errcheck // read-only file close errors are not critical
 
-	doc, err := html.Parse(file)
+	hash := sha256.New()
+
+	doc, err := html.Parse(io.TeeReader(file, hash))
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to parse HTML")
+		return nil, "", errors.Wrap(err, "failed to parse HTML")
 	}
 
-	entry := &models.AppleJournalEntry{FilePath: filePath}
-	p.extractFromNode(doc, entry)
+	// The tokenizer stops at the end of the document, hash what follows too.
+	if _, err := io.Copy(hash, file); err != nil {
+		return nil, "", errors.Wrap(err, "failed to read file")
+	}
+
+	b := &entryBuilder{
+		entry: &models.AppleJournalEntry{
+			FilePath:    p.displayPath(name),
+			ContentHash: hex.EncodeToString(hash.Sum(nil)),
+		},
+		sheet: parseStylesheet(doc),
+	}
+
+	p.extractFromNode(doc, b)
+	b.entry.Body = RenderMarkdown(b.entry.Blocks)
+
+	return b.entry, b.header, nil
+}
+
+// resolveDate dates the entry from its page header, falling back to the
+// first asset's metadata and then to the file name.
+func (p *AppleJournalParser) resolveDate(entry *models.AppleJournalEntry, header string, locale *Locale) {
+	entry.Date = locale.ParseDate(header)
 
 	if entry.Date.IsZero() {
 		entry.Date = p.extractDateFromAssets(entry.Assets)
 	}
 
 	if entry.Date.IsZero() {
-		entry.Date = extractDateFromFilename(filePath)
+		entry.Date = extractDateFromFilename(entry.FilePath)
 	}
-
-	return entry, nil
 }
 
 func (p *AppleJournalParser) extractDateFromAssets(assets []models.AppleJournalAsset) time.Time {
@@ -95,58 +266,39 @@ func (p *AppleJournalParser) extractDateFromAssets(assets []models.AppleJournalA
 	return models.CocoaTimestampToTime(meta.Date)
 }
 
-func (p *AppleJournalParser) extractFromNode(n *html.Node, entry *models.AppleJournalEntry) {
+func (p *AppleJournalParser) extractFromNode(n *html.Node, b *entryBuilder) {
 	if n.Type == html.ElementNode {
-		p.processElement(n, entry)
+		p.processElement(n, b)
 	}
 
 	for c := n.FirstChild; c != nil; c = c.NextSibling {
-		p.extractFromNode(c, entry)
+		p.extractFromNode(c, b)
 	}
 }
 
-func (p *AppleJournalParser) processElement(n *html.Node, entry *models.AppleJournalEntry) {
+func (p *AppleJournalParser) processElement(n *html.Node, b *entryBuilder) {
 	if n.Data == "div" {
-		p.processDivElement(n, entry)
+		p.processDivElement(n, b)
 	}
 }
 
-func (p *AppleJournalParser) processDivElement(n *html.Node, entry *models.AppleJournalEntry) {
+func (p *AppleJournalParser) processDivElement(n *html.Node, b *entryBuilder) {
 	class := getAttr(n, "class")
+	entry := b.entry
 
 	switch {
 	case strings.Contains(class, "pageHeader"):
-		entry.Date = parsePageHeaderDate(getTextContent(n))
+		b.header = strings.TrimSpace(getTextContent(n))
 	case strings.Contains(class, "title"):
 		entry.Title = strings.TrimSpace(getTextContent(n))
 	case strings.Contains(class, "gridItem"):
 		if asset := p.parseGridItem(n); asset != nil {
+			asset.Position = len(entry.Blocks)
 			entry.Assets = append(entry.Assets, *asset)
 		}
 	case strings.Contains(class, "bodyText"):
-		if text := extractBodyText(n); text != "" {
-			entry.Body = text
-		}
-	}
-}
-
-func parsePageHeaderDate(text string) time.Time {
-	text = strings.TrimSpace(text)
-
-	formats := []string{
-		"Monday, 2 January 2006",
-		"Monday, 02 January 2006",
-		"2 January 2006",
-		"02 January 2006",
-	}
-
-	for _, format := range formats {
-		if t, err := time.Parse(format, text); err == nil {
-			return t
-		}
+		entry.Blocks = append(entry.Blocks, extractBodyBlocks(n, b.sheet)...)
 	}
-
-	return time.Time{}
 }
 
 func extractDateFromFilename(filePath string) time.Time {
@@ -177,29 +329,60 @@ func (p *AppleJournalParser) parseGridItem(n *html.Node) *models.AppleJournalAss
 
 	filePath, ext := findMediaSrcInNode(n)
 	if filePath == "" {
-		filePath, ext = p.findResourceFile(id)
+		filePath, ext = p.findResourceFile(id, assetType)
 	}
 
-	return &models.AppleJournalAsset{
+	asset := &models.AppleJournalAsset{
 		ID:        id,
 		Type:      assetType,
 		FilePath:  filePath,
 		Extension: ext,
 	}
+
+	switch assetType {
+	case "photo":
+		if strings.Contains(class, livePhotoClass) {
+			p.pairLivePhoto(asset)
+		}
+	case "map":
+		asset.Place = p.mapPlace(n, id)
+	case "activity":
+		asset.Activity = p.motionActivity(n, id)
+	case "stateOfMind":
+		asset.Mood = p.stateOfMind(n, id)
+	}
+
+	return asset
 }
 
-func extractAssetType(class string) string {
-	typeMap := map[string]string{
-		"assetType_photo":          "photo",
-		"assetType_livePhoto":      "photo",
-		"assetType_video":          "video",
-		"assetType_genericMap":     "map",
-		"assetType_motionActivity": "activity",
-		"assetType_audio":          "audio",
-		"assetType_stateOfMind":    "stateOfMind",
+// assetTypeClasses maps the CSS classes of asset grid items to asset types.
+var assetTypeClasses = map[string]string{
+	"assetType_photo":          "photo",
+	"assetType_livePhoto":      "photo",
+	"assetType_video":          "video",
+	"assetType_genericMap":     "map",
+	"assetType_motionActivity": "activity",
+	"assetType_audio":          "audio",
+	"assetType_stateOfMind":    "stateOfMind",
+}
+
+// AssetTypes returns the sorted asset types the parser recognizes.
+func AssetTypes() []string {
+	var types []string
+
+	for _, assetType := range assetTypeClasses {
+		if !slices.Contains(types, assetType) {
+			types = append(types, assetType)
+		}
 	}
 
-	for cssClass, assetType := range typeMap {
+	slices.Sort(types)
+
+	return types
+}
+
+func extractAssetType(class string) string {
+	for cssClass, assetType := range assetTypeClasses {
 		if strings.Contains(class, cssClass) {
 			return assetType
 		}
@@ -269,57 +452,23 @@ func findSourceElement(n *html.Node) string {
 	return ""
 }
 
-func (p *AppleJournalParser) findResourceFile(uuid string) (filePath, ext string) {
-	resourcesDir := filepath.Join(p.basePath, "Resources")
-
-	entries, err := os.ReadDir(resourcesDir)
+func (p *AppleJournalParser) findResourceFile(uuid, assetType string) (filePath, ext string) {
+	catalog, err := p.Resources()
 	if err != nil {
 		return "", ""
 	}
 
-	for _, entry := range entries {
-		if !strings.HasPrefix(entry.Name(), uuid) {
-			continue
-		}
-
-		if strings.HasSuffix(entry.Name(), ".json") {
-			continue
-		}
-
-		ext := strings.TrimPrefix(filepath.Ext(entry.Name()), ".")
-
-		return filepath.Join("..", "Resources", entry.Name()), ext
-	}
-
-	return "", ""
-}
-
-func extractBodyText(n *html.Node) string {
-	var parts []string
-
-	collectBodyText(n, &parts)
-
-	return strings.Join(parts, "\n")
-}
-
-func collectBodyText(n *html.Node, parts *[]string) {
-	if n.Type == html.TextNode {
-		text := strings.TrimSpace(n.Data)
-		if text != "" {
-			*parts = append(*parts, text)
-		}
+	name := catalog.AssetFile(uuid, assetType)
+	if name == "" {
+		return "", ""
 	}
 
-	for c := n.FirstChild; c != nil; c = c.NextSibling {
-		collectBodyText(c, parts)
-	}
+	return path.Join("..", name), strings.TrimPrefix(path.Ext(name), ".")
 }
 
 // LoadResourceMeta loads the JSON metadata for a resource by UUID.
 func (p *AppleJournalParser) LoadResourceMeta(uuid string) (*models.AppleJournalResourceMeta, error) {
-	metaPath := filepath.Join(p.basePath, "Resources", uuid+".json")
-
-	data, err := os.ReadFile(filepath.Clean(metaPath))
+	data, err := fs.ReadFile(p.fsys, path.Join(resourcesDir, uuid+".json"))
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to read metadata")
 	}
@@ -332,22 +481,50 @@ func (p *AppleJournalParser) LoadResourceMeta(uuid string) (*models.AppleJournal
 	return &meta, nil
 }
 
-// GetResourceFilePath returns the full path to a resource file.
-func (p *AppleJournalParser) GetResourceFilePath(uuid string) string {
-	resourcesDir := filepath.Join(p.basePath, "Resources")
-
-	entries, err := os.ReadDir(resourcesDir)
+// AssetFilePath returns the path within the export of the resource file an
+// asset shows: the file its grid item links to when the export holds it, or
+// else the media file that fits the asset type. Use OpenResource to read it.
+func (p *AppleJournalParser) AssetFilePath(asset *models.AppleJournalAsset) string {
+	catalog, err := p.Resources()
 	if err != nil {
 		return ""
 	}
 
-	for _, entry := range entries {
-		if strings.HasPrefix(entry.Name(), uuid) && !strings.HasSuffix(entry.Name(), ".json") {
-			return filepath.Join(resourcesDir, entry.Name())
+	if src := path.Join(entriesDir, asset.FilePath); slices.Contains(catalog.MediaFiles(asset.ID), src) {
+		return src
+	}
+
+	return catalog.AssetFile(asset.ID, asset.Type)
+}
+
+// OpenResource opens a file by its path within the export.
+func (p *AppleJournalParser) OpenResource(name string) (fs.File, error) {
+	file, err := p.fsys.Open(name)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open resource")
+	}
+
+	return file, nil
+}
+
+// fsPath converts a path given by the caller into a path within the export.
+func (p *AppleJournalParser) fsPath(filePath string) string {
+	if p.basePath != "" {
+		if rel, err := filepath.Rel(p.basePath, filePath); err == nil && filepath.IsLocal(rel) {
+			return filepath.ToSlash(rel)
 		}
 	}
 
-	return ""
+	return path.Clean(filepath.ToSlash(filePath))
+}
+
+// displayPath returns the path shown to users for a file within the export.
+func (p *AppleJournalParser) displayPath(name string) string {
+	if p.basePath == "" {
+		return name
+	}
+
+	return filepath.Join(p.basePath, filepath.FromSlash(name))
 }
 
 func getAttr(n *html.Node, key string) string {
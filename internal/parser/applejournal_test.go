@@ -1,6 +1,9 @@
 package parser_test
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"testing"
@@ -8,6 +11,7 @@ import (
 
 	"github.com/stretchr/testify/require"
 
+	"github.com/kpod13/journal2day1/internal/models"
 	"github.com/kpod13/journal2day1/internal/parser"
 )
 
@@ -43,6 +47,46 @@ func TestParseAll(t *testing.T) {
 	require.Len(t, entries, 3)
 }
 
+func TestParseAllConcurrentJobsKeepsOrder(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	createDirs(t, tmpDir)
+
+	for i := 1; i <= 9; i++ {
+		writeNumberedEntry(t, tmpDir, i)
+	}
+
+	p := parser.NewAppleJournalParser(tmpDir)
+	p.SetJobs(4)
+
+	entries, err := p.ParseAll()
+	require.NoError(t, err)
+	require.Len(t, entries, 9)
+
+	for i, entry := range entries {
+		require.Equal(t, "2025-12-15_Entry_"+string(rune('1'+i))+".html", filepath.Base(entry.FilePath))
+	}
+}
+
+func TestParseEntryContentHash(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	setupTestStructure(t, tmpDir)
+
+	entryPath := filepath.Join(tmpDir, "Entries", "2025-12-15_Test.html")
+
+	data, err := os.ReadFile(entryPath)
+	require.NoError(t, err)
+
+	entry, err := parser.NewAppleJournalParser(tmpDir).ParseEntry(entryPath)
+	require.NoError(t, err)
+
+	sum := sha256.Sum256(data)
+	require.Equal(t, hex.EncodeToString(sum[:]), entry.ContentHash)
+}
+
 func TestParseEntryWithBody(t *testing.T) {
 	t.Parallel()
 
@@ -154,7 +198,7 @@ func writeNumberedEntry(t *testing.T, tmpDir string, num int) {
 	require.NoError(t, os.WriteFile(filename, []byte(content), 0o600))
 }
 
-func TestGetResourceFilePath(t *testing.T) {
+func TestAssetFilePath(t *testing.T) {
 	t.Parallel()
 
 	tmpDir := t.TempDir()
@@ -165,13 +209,13 @@ func TestGetResourceFilePath(t *testing.T) {
 
 	p := parser.NewAppleJournalParser(tmpDir)
 
-	path := p.GetResourceFilePath("TEST-UUID-5678")
+	path := p.AssetFilePath(&models.AppleJournalAsset{ID: "TEST-UUID-5678", Type: "photo"})
 
 	require.NotEmpty(t, path)
 	require.Contains(t, path, "TEST-UUID-5678.jpg")
 }
 
-func TestGetResourceFilePathNotFound(t *testing.T) {
+func TestAssetFilePathNotFound(t *testing.T) {
 	t.Parallel()
 
 	tmpDir := t.TempDir()
@@ -179,7 +223,7 @@ func TestGetResourceFilePathNotFound(t *testing.T) {
 
 	p := parser.NewAppleJournalParser(tmpDir)
 
-	path := p.GetResourceFilePath("NONEXISTENT-UUID")
+	path := p.AssetFilePath(&models.AppleJournalAsset{ID: "NONEXISTENT-UUID", Type: "photo"})
 
 	require.Empty(t, path)
 }
@@ -640,3 +684,193 @@ func TestParseEntryWithEmptyParagraph(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, "Non-empty", entry.Body)
 }
+
+func TestParseEntryBodyMarkdown(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		style    string
+		body     string
+		expected string
+	}{
+		{
+			name:     "inline formatting tags",
+			body:     `<p>Plain <b>bold</b> <i>italic</i> <s>gone</s> <b><i>both</i></b></p>`,
+			expected: "Plain **bold** *italic* ~~gone~~ ***both***",
+		},
+		{
+			name:     "spaces stay outside markers",
+			body:     `<p>Go<b> now </b>please</p>`,
+			expected: "Go **now** please",
+		},
+		{
+			name:     "underline stays plain without markdown syntax",
+			body:     `<p><u>underlined</u></p>`,
+			expected: "underlined",
+		},
+		{
+			name:     "class styles from stylesheet",
+			style:    `span.s1 {font-weight: bold} span.s2 {font-style: italic; text-decoration: line-through}`,
+			body:     `<p class="p1"><span class="s1">Strong</span> <span class="s2">old</span></p>`,
+			expected: "**Strong** ~~*old*~~",
+		},
+		{
+			name:     "inline style attribute",
+			body:     `<p><span style="font-weight: 700">heavy</span></p>`,
+			expected: "**heavy**",
+		},
+		{
+			name:     "headings",
+			body:     `<h1>Big</h1><h3>Small</h3><p>Text</p>`,
+			expected: "# Big\n### Small\nText",
+		},
+		{
+			name:     "nested lists",
+			body:     `<ul><li>One<ul><li>Inner</li></ul></li><li>Two</li></ul><ol start="3"><li>Third</li><li>Fourth</li></ol>`,
+			expected: "- One\n    - Inner\n- Two\n3. Third\n4. Fourth",
+		},
+		{
+			name:     "block quote",
+			body:     `<blockquote><p>Quoted</p><p>Again</p></blockquote><p>After</p>`,
+			expected: "> Quoted\n> Again\n\nAfter",
+		},
+		{
+			name:     "paragraph after list",
+			body:     `<ul><li>One</li></ul><p>After</p>`,
+			expected: "- One\n\nAfter",
+		},
+		{
+			name:     "paragraph after list in quote",
+			body:     `<blockquote><ul><li>One</li></ul><p>Quoted</p></blockquote>`,
+			expected: "> - One\n>\n> Quoted",
+		},
+		{
+			name:     "hyperlink",
+			body:     `<p>See <a href="https://example.com/a b">the <b>site</b></a>.</p>`,
+			expected: "See [the **site**](https://example.com/a%20b).",
+		},
+		{
+			name:     "line breaks",
+			body:     `<p>First<br>Second</p>`,
+			expected: "First\nSecond",
+		},
+		{
+			name:     "special characters escaped",
+			body:     `<p>2*3 = 6 and snake_case [x]</p><p># not a heading</p><p>1. not a list</p><p>- nor this</p>`,
+			expected: "2\\*3 = 6 and snake\\_case \\[x\\]\n\\# not a heading\n1\\. not a list\n\\- nor this",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			tmpDir := t.TempDir()
+			createDirs(t, tmpDir)
+
+			content := `<!DOCTYPE html>
+<html>
+<head><style type="text/css">` + tc.style + `</style></head>
+<body>
+<div class="pageHeader">Monday, 15 December 2025</div>
+<div class='bodyText'>` + tc.body + `</div>
+</body>
+</html>`
+
+			entryPath := filepath.Join(tmpDir, "Entries", "2025-12-15_Markdown.html")
+			require.NoError(t, os.WriteFile(entryPath, []byte(content), 0o600))
+
+			p := parser.NewAppleJournalParser(tmpDir)
+
+			entry, err := p.ParseEntry(entryPath)
+
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, entry.Body)
+		})
+	}
+}
+
+func TestParseEntryRecordsAssetPositions(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	createDirs(t, tmpDir)
+
+	content := `<!DOCTYPE html>
+<html>
+<body>
+<div class="pageHeader">Monday, 15 December 2025</div>
+<div class="assetGrid">
+    <div id="FIRST-UUID" class="gridItem assetType_photo"></div>
+</div>
+<div class='bodyText'><p>One</p><p>Two</p></div>
+<div class="assetGrid">
+    <div id="SECOND-UUID" class="gridItem assetType_photo"></div>
+</div>
+<div class='bodyText'><p>Three</p></div>
+</body>
+</html>`
+
+	entryPath := filepath.Join(tmpDir, "Entries", "2025-12-15_Positions.html")
+	require.NoError(t, os.WriteFile(entryPath, []byte(content), 0o600))
+
+	p := parser.NewAppleJournalParser(tmpDir)
+
+	entry, err := p.ParseEntry(entryPath)
+
+	require.NoError(t, err)
+	require.Len(t, entry.Blocks, 3)
+	require.Equal(t, "One\nTwo\nThree", entry.Body)
+	require.Len(t, entry.Assets, 2)
+	require.Equal(t, 0, entry.Assets[0].Position)
+	require.Equal(t, 2, entry.Assets[1].Position)
+}
+
+func writeBrokenEntry(t *testing.T, tmpDir string) {
+	t.Helper()
+
+	brokenPath := filepath.Join(tmpDir, "Entries", "2025-12-15_Broken.html")
+	require.NoError(t, os.Symlink(filepath.Join(tmpDir, "missing.html"), brokenPath))
+}
+
+func TestParseAllStrictStopsOnBrokenEntry(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	setupMultipleEntries(t, tmpDir)
+	writeBrokenEntry(t, tmpDir)
+
+	p := parser.NewAppleJournalParser(tmpDir)
+
+	_, err := p.ParseAll()
+
+	require.ErrorContains(t, err, "2025-12-15_Broken.html")
+}
+
+func TestParseAllLenientCollectsFailures(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	setupMultipleEntries(t, tmpDir)
+	writeBrokenEntry(t, tmpDir)
+
+	p := parser.NewAppleJournalParser(tmpDir)
+	p.SetLenient(true)
+
+	entries, err := p.ParseAll()
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+
+	failures := p.Failures()
+	require.Len(t, failures, 1)
+	require.Equal(t, filepath.Join(tmpDir, "Entries", "2025-12-15_Broken.html"), failures[0].Path)
+	require.ErrorIs(t, &failures[0], fs.ErrNotExist)
+	require.Contains(t, failures[0].Error(), "2025-12-15_Broken.html: ")
+}
+
+func TestAssetTypes(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, []string{"activity", "audio", "map", "photo", "stateOfMind", "video"}, parser.AssetTypes())
+}
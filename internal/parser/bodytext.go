@@ -0,0 +1,295 @@
+package parser
+
+import (
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+
+	"github.com/kpod13/journal2day1/internal/models"
+)
+
+// listState tracks an open <ul> or <ol> while walking the body.
+type listState struct {
+	ordered bool
+	next    int
+}
+
+// stylesheet maps CSS class names to their declarations.
+type stylesheet map[string]string
+
+// bodyTextBuilder walks a bodyText DOM subtree and splits it into blocks.
+type bodyTextBuilder struct {
+	sheet  stylesheet
+	blocks []models.TextBlock
+	cur    *models.TextBlock
+	lists  []listState
+	quotes int
+}
+
+var (
+	whitespaceRe = regexp.MustCompile(`[ \t\r\n\f]+`)
+	cssRuleRe    = regexp.MustCompile(`([^{}]+)\{([^{}]*)\}`)
+)
+
+func extractBodyBlocks(n *html.Node, sheet stylesheet) []models.TextBlock {
+	b := &bodyTextBuilder{sheet: sheet}
+	b.walkChildren(n, b.styleFor(n, models.TextStyle{}))
+	b.closeBlock()
+
+	return b.blocks
+}
+
+func (b *bodyTextBuilder) walkChildren(n *html.Node, style models.TextStyle) {
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		b.walk(c, style)
+	}
+}
+
+func (b *bodyTextBuilder) walk(n *html.Node, style models.TextStyle) {
+	switch n.Type {
+	case html.TextNode:
+		b.addText(n.Data, style)
+	case html.ElementNode:
+		b.walkElement(n, style)
+	default:
+		b.walkChildren(n, style)
+	}
+}
+
+func (b *bodyTextBuilder) walkElement(n *html.Node, style models.TextStyle) {
+	style = b.styleFor(n, style)
+
+	switch n.Data {
+	case "style", "script", "head", "title":
+		return
+	case "br":
+		b.addBreak(style)
+	case "p", "div", "pre":
+		b.walkBlock(n, style, models.TextBlock{Kind: models.BlockParagraph})
+	case "h1", "h2", "h3", "h4", "h5", "h6":
+		b.walkBlock(n, style, models.TextBlock{Kind: models.BlockHeading, Level: int(n.Data[1] - '0')})
+	case "ul", "ol":
+		b.walkList(n, style)
+	case "li":
+		b.walkListItem(n, style)
+	case "blockquote":
+		b.closeBlock()
+		b.quotes++
+		b.walkChildren(n, style)
+		b.closeBlock()
+		b.quotes--
+	default:
+		b.walkChildren(n, style)
+	}
+}
+
+func (b *bodyTextBuilder) walkBlock(n *html.Node, style models.TextStyle, block models.TextBlock) {
+	b.closeBlock()
+	b.openBlock(block)
+	b.walkChildren(n, style)
+	b.closeBlock()
+}
+
+func (b *bodyTextBuilder) walkList(n *html.Node, style models.TextStyle) {
+	list := listState{ordered: n.Data == "ol", next: 1}
+
+	if start, err := strconv.Atoi(getAttr(n, "start")); err == nil {
+		list.next = start
+	}
+
+	b.closeBlock()
+	b.lists = append(b.lists, list)
+	b.walkChildren(n, style)
+	b.closeBlock()
+	b.lists = b.lists[:len(b.lists)-1]
+}
+
+func (b *bodyTextBuilder) walkListItem(n *html.Node, style models.TextStyle) {
+	item := models.TextBlock{Kind: models.BlockListItem}
+
+	if depth := len(b.lists); depth > 0 {
+		list := &b.lists[depth-1]
+		item.Depth = depth - 1
+		item.Ordered = list.ordered
+		item.Index = list.next
+		list.next++
+	}
+
+	b.walkBlock(n, style, item)
+}
+
+func (b *bodyTextBuilder) openBlock(block models.TextBlock) {
+	block.Quote = b.quotes > 0
+	b.cur = &block
+}
+
+func (b *bodyTextBuilder) closeBlock() {
+	if b.cur == nil {
+		return
+	}
+
+	block := b.cur
+	b.cur = nil
+
+	trimBlockRuns(block)
+
+	if len(block.Runs) > 0 {
+		b.blocks = append(b.blocks, *block)
+	}
+}
+
+func (b *bodyTextBuilder) addText(text string, style models.TextStyle) {
+	text = whitespaceRe.ReplaceAllString(text, " ")
+
+	if b.cur == nil {
+		if strings.TrimSpace(text) == "" {
+			return
+		}
+
+		b.openBlock(models.TextBlock{Kind: models.BlockParagraph})
+	}
+
+	if len(b.cur.Runs) == 0 || strings.HasSuffix(b.cur.Runs[len(b.cur.Runs)-1].Text, "\n") {
+		text = strings.TrimLeft(text, " ")
+	}
+
+	if text == "" {
+		return
+	}
+
+	b.cur.Runs = append(b.cur.Runs, models.TextRun{Text: text, Style: style})
+}
+
+func (b *bodyTextBuilder) addBreak(style models.TextStyle) {
+	if b.cur == nil || len(b.cur.Runs) == 0 {
+		return
+	}
+
+	last := &b.cur.Runs[len(b.cur.Runs)-1]
+	last.Text = strings.TrimRight(last.Text, " ")
+
+	b.cur.Runs = append(b.cur.Runs, models.TextRun{Text: "\n", Style: style})
+}
+
+// trimBlockRuns removes trailing whitespace and line breaks from a block.
+func trimBlockRuns(block *models.TextBlock) {
+	for len(block.Runs) > 0 {
+		last := &block.Runs[len(block.Runs)-1]
+		last.Text = strings.TrimRight(last.Text, " \n")
+
+		if last.Text != "" {
+			break
+		}
+
+		block.Runs = block.Runs[:len(block.Runs)-1]
+	}
+}
+
+// styleFor applies tag semantics, class rules and the inline style attribute.
+func (b *bodyTextBuilder) styleFor(n *html.Node, style models.TextStyle) models.TextStyle {
+	if n.Type != html.ElementNode {
+		return style
+	}
+
+	switch n.Data {
+	case "b", "strong":
+		style.Bold = true
+	case "i", "em", "cite":
+		style.Italic = true
+	case "u", "ins":
+		style.Underline = true
+	case "s", "strike", "del":
+		style.Strikethrough = true
+	case "a":
+		if href := strings.TrimSpace(getAttr(n, "href")); href != "" {
+			style.Link = href
+		}
+	}
+
+	for _, class := range strings.Fields(getAttr(n, "class")) {
+		if decls, ok := b.sheet[class]; ok {
+			style = applyDeclarations(style, decls)
+		}
+	}
+
+	return applyDeclarations(style, getAttr(n, "style"))
+}
+
+// applyDeclarations applies the CSS declarations that affect inline formatting.
+func applyDeclarations(style models.TextStyle, decls string) models.TextStyle {
+	for _, decl := range strings.Split(decls, ";") {
+		name, value, ok := strings.Cut(decl, ":")
+		if !ok {
+			continue
+		}
+
+		name = strings.ToLower(strings.TrimSpace(name))
+		value = strings.ToLower(strings.TrimSpace(value))
+
+		switch name {
+		case "font-weight":
+			style.Bold = isBoldWeight(value)
+		case "font-style":
+			style.Italic = value == "italic" || value == "oblique"
+		case "text-decoration", "text-decoration-line":
+			style.Underline = strings.Contains(value, "underline")
+			style.Strikethrough = strings.Contains(value, "line-through")
+		case "font":
+			words := strings.Fields(value)
+			style.Bold = slices.Contains(words, "bold") || slices.Contains(words, "bolder")
+			style.Italic = slices.Contains(words, "italic") || slices.Contains(words, "oblique")
+		}
+	}
+
+	return style
+}
+
+func isBoldWeight(value string) bool {
+	if value == "bold" || value == "bolder" {
+		return true
+	}
+
+	weight, err := strconv.Atoi(value)
+
+	return err == nil && weight >= 600
+}
+
+// parseStylesheet collects class rules from every <style> element in the document.
+func parseStylesheet(doc *html.Node) stylesheet {
+	sheet := stylesheet{}
+
+	var walk func(n *html.Node)
+
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode && n.Data == "style" {
+			addStyleRules(sheet, getTextContent(n))
+		}
+
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+
+	walk(doc)
+
+	return sheet
+}
+
+func addStyleRules(sheet stylesheet, css string) {
+	for _, match := range cssRuleRe.FindAllStringSubmatch(css, -1) {
+		for _, selector := range strings.Split(match[1], ",") {
+			selector = strings.TrimSpace(selector)
+
+			dot := strings.LastIndex(selector, ".")
+			if dot < 0 || strings.ContainsAny(selector[dot:], " >+~:[") {
+				continue
+			}
+
+			class := selector[dot+1:]
+			sheet[class] += ";" + match[2]
+		}
+	}
+}
@@ -0,0 +1,288 @@
+package parser
+
+import (
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/pkg/errors"
+)
+
+// ErrUnknownLocale is returned when a locale name has no date table.
+var ErrUnknownLocale = errors.New("unknown locale")
+
+// dateOrder is the position of day, month and year in all-numeric dates.
+type dateOrder int
+
+const (
+	dayMonthYear dateOrder = iota
+	monthDayYear
+	yearMonthDay
+)
+
+const (
+	minFourDigitYear = 1000
+	maxDayOfMonth    = 31
+)
+
+// Locale describes how page header dates are written in one locale.
+type Locale struct {
+	Name    string
+	order   dateOrder
+	months  map[string]time.Month
+	ignored map[string]bool // weekday names and filler words
+}
+
+// Locales returns the names of the built-in locales in detection order.
+func Locales() []string {
+	names := make([]string, 0, len(builtinLocales))
+
+	for _, l := range builtinLocales {
+		names = append(names, l.Name)
+	}
+
+	return names
+}
+
+// LookupLocale returns the built-in locale with the given name.
+func LookupLocale(name string) (*Locale, error) {
+	for _, l := range builtinLocales {
+		if strings.EqualFold(l.Name, name) {
+			return l, nil
+		}
+	}
+
+	return nil, errors.Wrapf(ErrUnknownLocale, "%q (supported: %s)", name, strings.Join(Locales(), ", "))
+}
+
+// ParseDate parses a page header date such as "Monday, 15 December 2025".
+// It returns the zero time when the text is not a date in this locale.
+func (l *Locale) ParseDate(text string) time.Time {
+	var (
+		numbers []int
+		month   time.Month
+	)
+
+	for _, token := range tokenizeDate(strings.ToLower(text)) {
+		if n, err := strconv.Atoi(token); err == nil {
+			numbers = append(numbers, n)
+
+			continue
+		}
+
+		if l.ignored[token] {
+			continue
+		}
+
+		if l.months[token] == 0 || month != 0 {
+			return time.Time{}
+		}
+
+		month = l.months[token]
+	}
+
+	if month != 0 {
+		return dateFromNamedMonth(numbers, month)
+	}
+
+	return l.dateFromNumbers(numbers)
+}
+
+func dateFromNamedMonth(numbers []int, month time.Month) time.Time {
+	if len(numbers) != 2 {
+		return time.Time{}
+	}
+
+	day, year := numbers[0], numbers[1]
+	if day > maxDayOfMonth {
+		day, year = year, day
+	}
+
+	return validDate(year, month, day)
+}
+
+func (l *Locale) dateFromNumbers(numbers []int) time.Time {
+	if len(numbers) != 3 {
+		return time.Time{}
+	}
+
+	order := l.order
+	if numbers[0] >= minFourDigitYear {
+		order = yearMonthDay
+	}
+
+	switch order {
+	case monthDayYear:
+		return validDate(numbers[2], time.Month(numbers[0]), numbers[1])
+	case yearMonthDay:
+		return validDate(numbers[0], time.Month(numbers[1]), numbers[2])
+	default:
+		return validDate(numbers[2], time.Month(numbers[1]), numbers[0])
+	}
+}
+
+// validDate builds a UTC date, rejecting values that time.Date would normalize.
+func validDate(year int, month time.Month, day int) time.Time {
+	if year < minFourDigitYear || month < time.January || month > time.December || day < 1 {
+		return time.Time{}
+	}
+
+	t := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	if t.Day() != day {
+		return time.Time{}
+	}
+
+	return t
+}
+
+// tokenizeDate splits text into runs of letters and runs of digits.
+func tokenizeDate(text string) []string {
+	var (
+		tokens  []string
+		current []rune
+		digits  bool
+	)
+
+	flush := func() {
+		if len(current) > 0 {
+			tokens = append(tokens, string(current))
+			current = current[:0]
+		}
+	}
+
+	for _, r := range text {
+		isDigit := unicode.IsDigit(r)
+
+		if !isDigit && !unicode.IsLetter(r) {
+			flush()
+
+			continue
+		}
+
+		if len(current) > 0 && isDigit != digits {
+			flush()
+		}
+
+		digits = isDigit
+		current = append(current, r)
+	}
+
+	flush()
+
+	return tokens
+}
+
+// detectLocale picks the locale that understands most of the given headers.
+// Ties are resolved by the order of the built-in locales.
+func detectLocale(headers []string) *Locale {
+	best, bestCount := builtinLocales[0], 0
+
+	for _, l := range builtinLocales {
+		count := 0
+
+		for _, header := range headers {
+			if !l.ParseDate(header).IsZero() {
+				count++
+			}
+		}
+
+		if count > bestCount {
+			best, bestCount = l, count
+		}
+	}
+
+	return best
+}
+
+func newLocale(name string, order dateOrder, months [12][]string, ignored ...string) *Locale {
+	l := &Locale{
+		Name:    name,
+		order:   order,
+		months:  make(map[string]time.Month),
+		ignored: make(map[string]bool, len(ignored)),
+	}
+
+	for i, names := range months {
+		for _, n := range names {
+			l.months[n] = time.Month(i + 1)
+		}
+	}
+
+	for _, word := range ignored {
+		l.ignored[word] = true
+	}
+
+	return l
+}
+
+var (
+	englishMonths = [12][]string{
+		{"january", "jan"}, {"february", "feb"}, {"march", "mar"}, {"april", "apr"},
+		{"may"}, {"june", "jun"}, {"july", "jul"}, {"august", "aug"},
+		{"september", "sep", "sept"}, {"october", "oct"}, {"november", "nov"}, {"december", "dec"},
+	}
+	englishIgnored = []string{
+		"monday", "tuesday", "wednesday", "thursday", "friday", "saturday", "sunday",
+		"mon", "tue", "tues", "wed", "thu", "thur", "thurs", "fri", "sat", "sun",
+		"st", "nd", "rd", "th", "of",
+	}
+
+	builtinLocales = []*Locale{
+		newLocale("en", dayMonthYear, englishMonths, englishIgnored...),
+		newLocale("en-US", monthDayYear, englishMonths, englishIgnored...),
+		newLocale("de", dayMonthYear, [12][]string{
+			{"januar", "jänner", "jan"}, {"februar", "feb"}, {"märz", "mär", "mrz"}, {"april", "apr"},
+			{"mai"}, {"juni", "jun"}, {"juli", "jul"}, {"august", "aug"},
+			{"september", "sep", "sept"}, {"oktober", "okt"}, {"november", "nov"}, {"dezember", "dez"},
+		},
+			"montag", "dienstag", "mittwoch", "donnerstag", "freitag", "samstag", "sonnabend", "sonntag",
+			"mo", "di", "mi", "do", "fr", "sa", "so",
+		),
+		newLocale("fr", dayMonthYear, [12][]string{
+			{"janvier", "janv"}, {"février", "févr", "fevrier"}, {"mars"}, {"avril", "avr"},
+			{"mai"}, {"juin"}, {"juillet", "juil"}, {"août", "aout"},
+			{"septembre", "sept"}, {"octobre", "oct"}, {"novembre", "nov"}, {"décembre", "déc", "decembre"},
+		},
+			"lundi", "mardi", "mercredi", "jeudi", "vendredi", "samedi", "dimanche",
+			"lun", "mar", "mer", "jeu", "ven", "sam", "dim", "er", "le",
+		),
+		newLocale("es", dayMonthYear, [12][]string{
+			{"enero", "ene"}, {"febrero", "feb"}, {"marzo", "mar"}, {"abril", "abr"},
+			{"mayo", "may"}, {"junio", "jun"}, {"julio", "jul"}, {"agosto", "ago"},
+			{"septiembre", "setiembre", "sept", "sep"}, {"octubre", "oct"}, {"noviembre", "nov"}, {"diciembre", "dic"},
+		},
+			"lunes", "martes", "miércoles", "jueves", "viernes", "sábado", "domingo",
+			"lun", "mié", "jue", "vie", "sáb", "dom", "de", "del",
+		),
+		newLocale("it", dayMonthYear, [12][]string{
+			{"gennaio", "gen"}, {"febbraio", "feb"}, {"marzo", "mar"}, {"aprile", "apr"},
+			{"maggio", "mag"}, {"giugno", "giu"}, {"luglio", "lug"}, {"agosto", "ago"},
+			{"settembre", "set"}, {"ottobre", "ott"}, {"novembre", "nov"}, {"dicembre", "dic"},
+		},
+			"lunedì", "martedì", "mercoledì", "giovedì", "venerdì", "sabato", "domenica",
+			"lun", "mer", "gio", "ven", "sab", "dom",
+		),
+		newLocale("ru", dayMonthYear, [12][]string{
+			{"январь", "января", "янв"}, {"февраль", "февраля", "фев"}, {"март", "марта", "мар"},
+			{"апрель", "апреля", "апр"}, {"май", "мая"}, {"июнь", "июня", "июн"},
+			{"июль", "июля", "июл"}, {"август", "августа", "авг"}, {"сентябрь", "сентября", "сен", "сент"},
+			{"октябрь", "октября", "окт"}, {"ноябрь", "ноября", "ноя", "нояб"}, {"декабрь", "декабря", "дек"},
+		},
+			"понедельник", "вторник", "среда", "четверг", "пятница", "суббота", "воскресенье",
+			"пн", "вт", "ср", "чт", "пт", "сб", "вс", "г", "года",
+		),
+		newLocale("bg", dayMonthYear, [12][]string{
+			{"януари", "яну"}, {"февруари", "фев"}, {"март", "мар"}, {"април", "апр"},
+			{"май"}, {"юни"}, {"юли"}, {"август", "авг"},
+			{"септември", "сеп"}, {"октомври", "окт"}, {"ноември", "ное"}, {"декември", "дек"},
+		},
+			"понеделник", "вторник", "сряда", "четвъртък", "петък", "събота", "неделя",
+			"пн", "вт", "ср", "чт", "пт", "сб", "нд", "г", "година",
+		),
+		newLocale("ja", yearMonthDay, [12][]string{},
+			"月曜日", "火曜日", "水曜日", "木曜日", "金曜日", "土曜日", "日曜日",
+			"月", "火", "水", "木", "金", "土", "日", "年",
+		),
+	}
+)
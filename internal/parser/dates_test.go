@@ -0,0 +1,143 @@
+package parser_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/kpod13/journal2day1/internal/parser"
+)
+
+func TestLocaleParseDate(t *testing.T) {
+	t.Parallel()
+
+	expected := time.Date(2025, 12, 15, 0, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		locale string
+		header string
+	}{
+		{locale: "en", header: "Monday, 15 December 2025"},
+		{locale: "en", header: "15/12/2025"},
+		{locale: "en-US", header: "Monday, December 15, 2025"},
+		{locale: "en-US", header: "12/15/2025"},
+		{locale: "de", header: "Montag, 15. Dezember 2025"},
+		{locale: "de", header: "15.12.2025"},
+		{locale: "fr", header: "lundi 15 décembre 2025"},
+		{locale: "es", header: "lunes, 15 de diciembre de 2025"},
+		{locale: "it", header: "lunedì 15 dicembre 2025"},
+		{locale: "ru", header: "понедельник, 15 декабря 2025 г."},
+		{locale: "bg", header: "понеделник, 15 декември 2025 г."},
+		{locale: "ja", header: "2025年12月15日 月曜日"},
+		{locale: "ja", header: "2025年12月15日(月)"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.locale+" "+tc.header, func(t *testing.T) {
+			t.Parallel()
+
+			locale, err := parser.LookupLocale(tc.locale)
+			require.NoError(t, err)
+
+			require.Equal(t, expected, locale.ParseDate(tc.header))
+		})
+	}
+}
+
+func TestLocaleParseDateRejectsOtherLocales(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		locale string
+		header string
+	}{
+		{locale: "en", header: "Montag, 15. Dezember 2025"},
+		{locale: "de", header: "lundi 15 décembre 2025"},
+		{locale: "ru", header: "понеделник, 15 декември 2025 г."},
+		{locale: "en", header: "12/15/2025"},
+		{locale: "en", header: "31 February 2025"},
+		{locale: "en", header: "December 2025"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.locale+" "+tc.header, func(t *testing.T) {
+			t.Parallel()
+
+			locale, err := parser.LookupLocale(tc.locale)
+			require.NoError(t, err)
+
+			require.True(t, locale.ParseDate(tc.header).IsZero())
+		})
+	}
+}
+
+func TestLookupLocaleUnknown(t *testing.T) {
+	t.Parallel()
+
+	_, err := parser.LookupLocale("xx")
+
+	require.ErrorIs(t, err, parser.ErrUnknownLocale)
+}
+
+func TestParseAllDetectsLocale(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	createDirs(t, tmpDir)
+
+	// 03/04/2025 is ambiguous on its own; 12/25/2025 only parses month-first.
+	writeHeaderEntry(t, tmpDir, "a.html", "03/04/2025")
+	writeHeaderEntry(t, tmpDir, "b.html", "12/25/2025")
+
+	p := parser.NewAppleJournalParser(tmpDir)
+
+	entries, err := p.ParseAll()
+
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	require.Equal(t, time.Date(2025, 3, 4, 0, 0, 0, 0, time.UTC), entries[0].Date)
+	require.Equal(t, time.Date(2025, 12, 25, 0, 0, 0, 0, time.UTC), entries[1].Date)
+}
+
+func TestParseAllWithFixedLocale(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	createDirs(t, tmpDir)
+
+	writeHeaderEntry(t, tmpDir, "a.html", "03/04/2025")
+
+	p := parser.NewAppleJournalParser(tmpDir)
+	require.NoError(t, p.SetLocale("de"))
+
+	entries, err := p.ParseAll()
+
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	require.Equal(t, time.Date(2025, 4, 3, 0, 0, 0, 0, time.UTC), entries[0].Date)
+}
+
+func TestSetLocaleUnknown(t *testing.T) {
+	t.Parallel()
+
+	p := parser.NewAppleJournalParser(t.TempDir())
+
+	require.ErrorIs(t, p.SetLocale("klingon"), parser.ErrUnknownLocale)
+}
+
+func writeHeaderEntry(t *testing.T, tmpDir, name, header string) {
+	t.Helper()
+
+	content := `<!DOCTYPE html>
+<html>
+<body>
+<div class="pageHeader">` + header + `</div>
+<div class='title'>Locale Entry</div>
+</body>
+</html>`
+
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "Entries", name), []byte(content), 0o600))
+}
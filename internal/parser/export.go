@@ -0,0 +1,134 @@
+package parser
+
+import (
+	"archive/zip"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Sentinel errors for opening exports.
+var (
+	ErrMissingEntries     = errors.New("export does not contain Entries directory")
+	ErrUnsupportedArchive = errors.New("unsupported export archive")
+)
+
+// IsArchive reports whether the path names a supported export archive.
+func IsArchive(exportPath string) bool {
+	return archiveKind(exportPath) != ""
+}
+
+func archiveKind(exportPath string) string {
+	lower := strings.ToLower(exportPath)
+
+	switch {
+	case strings.HasSuffix(lower, ".zip"):
+		return "zip"
+	case strings.HasSuffix(lower, ".tar.gz"), strings.HasSuffix(lower, ".tgz"):
+		return "tar.gz"
+	}
+
+	return ""
+}
+
+// OpenExport opens an Apple Journal export directory, ZIP archive or tar.gz
+// archive. The export may be nested in a top-level folder of the archive.
+// Call Close on the returned parser when done.
+func OpenExport(exportPath string) (*AppleJournalParser, error) {
+	info, err := os.Stat(exportPath)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open export")
+	}
+
+	if info.IsDir() {
+		return NewAppleJournalParser(exportPath), nil
+	}
+
+	switch archiveKind(exportPath) {
+	case "zip":
+		return openZipExport(exportPath)
+	case "tar.gz":
+		return openTarGzExport(exportPath)
+	}
+
+	return nil, errors.Wrapf(ErrUnsupportedArchive, "%s", exportPath)
+}
+
+func openZipExport(exportPath string) (*AppleJournalParser, error) {
+	archive, err := zip.OpenReader(exportPath)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open ZIP archive")
+	}
+
+	root, err := findExportRoot(archive)
+	if err != nil {
+		_ = archive.Close() //nolint:errcheck // the root lookup error is more relevant
+
+		return nil, errors.Wrapf(err, "%s", exportPath)
+	}
+
+	p := NewAppleJournalParserFS(root)
+	p.closer = archive
+
+	return p, nil
+}
+
+// openTarGzExport indexes the archive and serves the export from it without
+// unpacking. Close releases the decompression streams left open.
+func openTarGzExport(exportPath string) (*AppleJournalParser, error) {
+	archive, err := indexTarGz(exportPath)
+	if err != nil {
+		return nil, err
+	}
+
+	root, err := findExportRoot(archive)
+	if err != nil {
+		_ = archive.Close() //nolint:errcheck // the root lookup error is more relevant
+
+		return nil, errors.Wrapf(err, "%s", exportPath)
+	}
+
+	p := NewAppleJournalParserFS(root)
+	p.closer = archive
+
+	return p, nil
+}
+
+// findExportRoot returns the directory of fsys that contains Entries, either
+// the root itself or one of its top-level folders.
+func findExportRoot(fsys fs.FS) (fs.FS, error) {
+	if isDir(fsys, entriesDir) {
+		return fsys, nil
+	}
+
+	dirs, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read archive")
+	}
+
+	for _, dir := range dirs {
+		if !dir.IsDir() || strings.HasPrefix(dir.Name(), "__MACOSX") {
+			continue
+		}
+
+		if isDir(fsys, path.Join(dir.Name(), entriesDir)) {
+			sub, err := fs.Sub(fsys, dir.Name())
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to open export folder")
+			}
+
+			return sub, nil
+		}
+	}
+
+	return nil, ErrMissingEntries
+}
+
+func isDir(fsys fs.FS, name string) bool {
+	info, err := fs.Stat(fsys, name)
+
+	return err == nil && info.IsDir()
+}
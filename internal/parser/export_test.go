@@ -0,0 +1,274 @@
+package parser_test
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/kpod13/journal2day1/internal/parser"
+)
+
+const archiveEntryHTML = `<!DOCTYPE html>
+<html>
+<body>
+<div class="pageHeader">Monday, 15 December 2025</div>
+<div class="assetGrid">
+    <div id="ARCHIVE-UUID" class="gridItem assetType_photo"></div>
+</div>
+<div class='title'>Archived Entry</div>
+</body>
+</html>`
+
+func archiveFiles(prefix string) map[string]string {
+	return map[string]string{
+		prefix + "Entries/2025-12-15_Archived.html": archiveEntryHTML,
+		prefix + "Resources/ARCHIVE-UUID.jpg":       "fake image",
+		prefix + "Resources/ARCHIVE-UUID.json":      `{"date": 784043393, "placeName": "Sofia"}`,
+	}
+}
+
+func TestOpenExportZip(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name   string
+		prefix string
+	}{
+		{name: "export at archive root", prefix: ""},
+		{name: "export in top-level folder", prefix: "AppleJournalEntries/"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			archivePath := filepath.Join(t.TempDir(), "export.zip")
+			writeZip(t, archivePath, archiveFiles(tc.prefix))
+
+			p, err := parser.OpenExport(archivePath)
+			require.NoError(t, err)
+
+			defer func() { _ = p.Close() }() //nolint:errcheck // test cleanup
+
+			verifyArchivedExport(t, p)
+		})
+	}
+}
+
+func TestOpenExportTarGz(t *testing.T) {
+	t.Parallel()
+
+	archivePath := filepath.Join(t.TempDir(), "export.tar.gz")
+	writeTarGz(t, archivePath, archiveFiles("./AppleJournalEntries/"))
+
+	p, err := parser.OpenExport(archivePath)
+	require.NoError(t, err)
+
+	verifyArchivedExport(t, p)
+	require.NoError(t, p.Close())
+}
+
+func TestOpenExportTarGzStreamsMedia(t *testing.T) {
+	t.Parallel()
+
+	files := archiveFiles("")
+	media := make(map[string]string)
+
+	for i := range 12 {
+		name := fmt.Sprintf("Resources/MEDIA-%02d.mov", i)
+		media[name] = strings.Repeat(fmt.Sprintf("%02d", i), 1000+i*7919)
+		files[name] = media[name]
+	}
+
+	archivePath := filepath.Join(t.TempDir(), "export.tar.gz")
+	writeTarGz(t, archivePath, files)
+
+	p, err := parser.OpenExport(archivePath)
+	require.NoError(t, err)
+
+	defer func() { _ = p.Close() }() //nolint:errcheck // test cleanup
+
+	readResource := func(name string) string {
+		file, err := p.OpenResource(name)
+		require.NoError(t, err)
+
+		defer func() { _ = file.Close() }() //nolint:errcheck // test cleanup
+
+		info, err := file.Stat()
+		require.NoError(t, err)
+		require.Equal(t, int64(len(media[name])), info.Size())
+
+		data, err := io.ReadAll(file)
+		require.NoError(t, err)
+
+		return string(data)
+	}
+
+	names := slices.Sorted(maps.Keys(media))
+
+	// Backwards, so that no idle stream can be resumed.
+	for _, name := range slices.Backward(names) {
+		require.Equal(t, media[name], readResource(name), name)
+	}
+
+	var wg sync.WaitGroup
+
+	for _, name := range names {
+		wg.Go(func() {
+			file, err := p.OpenResource(name)
+			if err != nil {
+				t.Error(err)
+
+				return
+			}
+
+			defer func() { _ = file.Close() }() //nolint:errcheck // test cleanup
+
+			data, err := io.ReadAll(file)
+			if err != nil || string(data) != media[name] {
+				t.Errorf("%s: read %d bytes, %v", name, len(data), err)
+			}
+		})
+	}
+
+	wg.Wait()
+
+	// A stream left in the middle of a member is resumed by the next one.
+	file, err := p.OpenResource(names[0])
+	require.NoError(t, err)
+
+	_, err = io.ReadFull(file, make([]byte, 10))
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+	require.Equal(t, media[names[1]], readResource(names[1]))
+}
+
+func TestOpenExportTarGzRejectsTraversal(t *testing.T) {
+	t.Parallel()
+
+	archivePath := filepath.Join(t.TempDir(), "export.tgz")
+	writeTarGz(t, archivePath, map[string]string{"../evil.html": "evil"})
+
+	_, err := parser.OpenExport(archivePath)
+
+	require.Error(t, err)
+}
+
+func TestOpenExportMissingEntries(t *testing.T) {
+	t.Parallel()
+
+	archivePath := filepath.Join(t.TempDir(), "export.zip")
+	writeZip(t, archivePath, map[string]string{"Other/file.txt": "text"})
+
+	_, err := parser.OpenExport(archivePath)
+
+	require.ErrorIs(t, err, parser.ErrMissingEntries)
+}
+
+func TestOpenExportUnsupportedFile(t *testing.T) {
+	t.Parallel()
+
+	filePath := filepath.Join(t.TempDir(), "export.rar")
+	require.NoError(t, os.WriteFile(filePath, []byte("data"), 0o600))
+
+	_, err := parser.OpenExport(filePath)
+
+	require.ErrorIs(t, err, parser.ErrUnsupportedArchive)
+}
+
+func TestOpenExportDirectory(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	setupMultipleEntries(t, tmpDir)
+
+	p, err := parser.OpenExport(tmpDir)
+	require.NoError(t, err)
+
+	entries, err := p.ParseAll()
+
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+	require.NoError(t, p.Close())
+}
+
+func verifyArchivedExport(t *testing.T, p *parser.AppleJournalParser) {
+	t.Helper()
+
+	entries, err := p.ParseAll()
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	require.Equal(t, "Archived Entry", entries[0].Title)
+	require.Equal(t, "jpg", entries[0].Assets[0].Extension)
+
+	meta, err := p.LoadResourceMeta("ARCHIVE-UUID")
+	require.NoError(t, err)
+	require.Equal(t, "Sofia", meta.PlaceName)
+
+	resourcePath := p.AssetFilePath(&entries[0].Assets[0])
+	require.Equal(t, "Resources/ARCHIVE-UUID.jpg", resourcePath)
+
+	file, err := p.OpenResource(resourcePath)
+	require.NoError(t, err)
+
+	data, err := io.ReadAll(file)
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+	require.Equal(t, "fake image", string(data))
+}
+
+func writeZip(t *testing.T, archivePath string, files map[string]string) {
+	t.Helper()
+
+	var buf bytes.Buffer
+
+	w := zip.NewWriter(&buf)
+
+	for name, content := range files {
+		f, err := w.Create(name)
+		require.NoError(t, err)
+
+		_, err = f.Write([]byte(content))
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, w.Close())
+	require.NoError(t, os.WriteFile(archivePath, buf.Bytes(), 0o600))
+}
+
+func writeTarGz(t *testing.T, archivePath string, files map[string]string) {
+	t.Helper()
+
+	var buf bytes.Buffer
+
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+
+	for name, content := range files {
+		require.NoError(t, tw.WriteHeader(&tar.Header{
+			Name:     name,
+			Mode:     0o600,
+			Size:     int64(len(content)),
+			Typeflag: tar.TypeReg,
+		}))
+
+		_, err := tw.Write([]byte(content))
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, tw.Close())
+	require.NoError(t, gz.Close())
+	require.NoError(t, os.WriteFile(archivePath, buf.Bytes(), 0o600))
+}
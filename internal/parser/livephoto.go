@@ -0,0 +1,34 @@
+package parser
+
+import (
+	"path"
+	"strings"
+
+	"github.com/kpod13/journal2day1/internal/models"
+)
+
+// livePhotoClass is the CSS class of Live Photo grid items. Live Photos are
+// photo assets whose motion video is kept with them.
+const livePhotoClass = "assetType_livePhoto"
+
+// pairLivePhoto pairs a Live Photo with its motion video when its resources
+// hold both a still image and a video, as Live Photos are exported. The
+// asset's file becomes the still, whichever file its grid item showed.
+func (p *AppleJournalParser) pairLivePhoto(asset *models.AppleJournalAsset) {
+	catalog, err := p.Resources()
+	if err != nil {
+		return
+	}
+
+	still, motion := catalog.LivePhotoFiles(asset.ID)
+	if still == "" || motion == "" {
+		return
+	}
+
+	asset.FilePath = path.Join("..", still)
+	asset.Extension = strings.TrimPrefix(path.Ext(still), ".")
+	asset.Motion = &models.MotionVideo{
+		FilePath:  path.Join("..", motion),
+		Extension: strings.TrimPrefix(path.Ext(motion), "."),
+	}
+}
@@ -0,0 +1,84 @@
+package parser_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/kpod13/journal2day1/internal/models"
+	"github.com/kpod13/journal2day1/internal/parser"
+)
+
+func TestParseEntryLivePhoto(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		class     string
+		item      string
+		resources []string
+		wantPath  string
+		wantExt   string
+		want      *models.MotionVideo
+	}{
+		{
+			name:      "still and motion video",
+			class:     "assetType_livePhoto",
+			item:      `<video src="../Resources/LIVE-UUID.MOV"></video>`,
+			resources: []string{"LIVE-UUID.MOV", "LIVE-UUID.heic", "LIVE-UUID.json"},
+			wantPath:  "../Resources/LIVE-UUID.heic",
+			wantExt:   "heic",
+			want:      &models.MotionVideo{FilePath: "../Resources/LIVE-UUID.MOV", Extension: "MOV"},
+		},
+		{
+			name:      "plain photo with a video next to it",
+			class:     "assetType_photo",
+			resources: []string{"LIVE-UUID.MOV", "LIVE-UUID.jpg"},
+			wantPath:  "../Resources/LIVE-UUID.jpg",
+			wantExt:   "jpg",
+		},
+		{
+			name:      "still only",
+			class:     "assetType_livePhoto",
+			resources: []string{"LIVE-UUID.heic"},
+			wantPath:  "../Resources/LIVE-UUID.heic",
+			wantExt:   "heic",
+		},
+		{
+			name:      "motion video only",
+			class:     "assetType_livePhoto",
+			resources: []string{"LIVE-UUID.mov"},
+			wantPath:  "../Resources/LIVE-UUID.mov",
+			wantExt:   "mov",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			tmpDir := t.TempDir()
+			createDirs(t, tmpDir)
+
+			for _, name := range tt.resources {
+				require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "Resources", name), []byte(name), 0o600))
+			}
+
+			content := `<div class="pageHeader">Monday, 15 December 2025</div>
+<div class="assetGrid"><div id="LIVE-UUID" class="gridItem ` + tt.class + `">` + tt.item + `</div></div>`
+
+			entryPath := filepath.Join(tmpDir, "Entries", "2025-12-15_Live.html")
+			require.NoError(t, os.WriteFile(entryPath, []byte(content), 0o600))
+
+			entry, err := parser.NewAppleJournalParser(tmpDir).ParseEntry(entryPath)
+			require.NoError(t, err)
+			require.Len(t, entry.Assets, 1)
+			require.Equal(t, "photo", entry.Assets[0].Type)
+			require.Equal(t, tt.wantPath, entry.Assets[0].FilePath)
+			require.Equal(t, tt.wantExt, entry.Assets[0].Extension)
+			require.Equal(t, tt.want, entry.Assets[0].Motion)
+		})
+	}
+}
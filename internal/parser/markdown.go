@@ -0,0 +1,231 @@
+package parser
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/kpod13/journal2day1/internal/models"
+)
+
+const listIndent = "    "
+
+var (
+	markdownEscaper = strings.NewReplacer(
+		`\`, `\\`,
+		"*", `\*`,
+		"_", `\_`,
+		"`", "\\`",
+		"[", `\[`,
+		"]", `\]`,
+		"~", `\~`,
+	)
+	urlEscaper = strings.NewReplacer(" ", "%20", "(", "%28", ")", "%29")
+
+	orderedMarkerRe = regexp.MustCompile(`^(\d+)([.)])`)
+)
+
+// RenderMarkdown renders body blocks as Day One flavoured Markdown, one line per block.
+// A blank line ends quotes and lists, so the block after them is not read as
+// their lazy continuation.
+func RenderMarkdown(blocks []models.TextBlock) string {
+	var out strings.Builder
+
+	for i := range blocks {
+		if i > 0 {
+			out.WriteString(blockSeparator(&blocks[i-1], &blocks[i]))
+		}
+
+		out.WriteString(renderMarkdownBlock(&blocks[i]))
+	}
+
+	return out.String()
+}
+
+// blockSeparator returns the line break between two consecutive blocks.
+func blockSeparator(prev, next *models.TextBlock) string {
+	listEnds := prev.Kind == models.BlockListItem && next.Kind != models.BlockListItem
+
+	switch {
+	case prev.Quote && !next.Quote:
+		return "\n\n"
+	case listEnds && prev.Quote:
+		return "\n>\n"
+	case listEnds:
+		return "\n\n"
+	}
+
+	return "\n"
+}
+
+func renderMarkdownBlock(block *models.TextBlock) string {
+	var prefix string
+
+	switch block.Kind {
+	case models.BlockHeading:
+		prefix = strings.Repeat("#", block.Level) + " "
+	case models.BlockListItem:
+		marker := "- "
+
+		if block.Ordered {
+			marker = strconv.Itoa(block.Index) + ". "
+		}
+
+		prefix = strings.Repeat(listIndent, block.Depth) + marker
+	}
+
+	continuation := ""
+
+	if block.Kind == models.BlockListItem {
+		continuation = strings.Repeat(" ", len(prefix))
+	}
+
+	if block.Quote {
+		prefix = "> " + prefix
+		continuation = "> " + continuation
+	}
+
+	lines := strings.Split(renderInline(block.Runs), "\n")
+	lines[0] = prefix + lines[0]
+
+	for i := 1; i < len(lines); i++ {
+		lines[i] = continuation + lines[i]
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// markdownWriter renders inline runs while tracking the start of each line,
+// where block-level Markdown characters need escaping.
+type markdownWriter struct {
+	out         strings.Builder
+	atLineStart bool
+}
+
+func renderInline(runs []models.TextRun) string {
+	w := &markdownWriter{atLineStart: true}
+
+	for start := 0; start < len(runs); {
+		end := start + 1
+
+		for end < len(runs) && runs[end].Style.Link == runs[start].Style.Link {
+			end++
+		}
+
+		w.writeLinkGroup(runs[start:end])
+		start = end
+	}
+
+	return w.out.String()
+}
+
+func (w *markdownWriter) writeLinkGroup(runs []models.TextRun) {
+	link := runs[0].Style.Link
+	if link != "" {
+		w.out.WriteString("[")
+		w.atLineStart = false
+	}
+
+	for start := 0; start < len(runs); {
+		end := start + 1
+
+		for end < len(runs) && sameFormatting(runs[end].Style, runs[start].Style) {
+			end++
+		}
+
+		var text strings.Builder
+
+		for _, run := range runs[start:end] {
+			text.WriteString(run.Text)
+		}
+
+		w.writeStyled(text.String(), runs[start].Style)
+		start = end
+	}
+
+	if link != "" {
+		w.out.WriteString("](" + urlEscaper.Replace(link) + ")")
+	}
+}
+
+// writeStyled wraps each line of text in emphasis markers. Markers hug the
+// text, so surrounding spaces are moved outside of them.
+func (w *markdownWriter) writeStyled(text string, style models.TextStyle) {
+	open, closing := emphasisMarkers(style)
+
+	for i, line := range strings.Split(text, "\n") {
+		if i > 0 {
+			w.out.WriteString("\n")
+			w.atLineStart = true
+		}
+
+		core := strings.TrimSpace(line)
+		if core == "" || open == "" {
+			w.writeText(line)
+
+			continue
+		}
+
+		lead := line[:strings.Index(line, core)]
+		trail := line[len(lead)+len(core):]
+
+		w.writeText(lead)
+		w.out.WriteString(open)
+		w.atLineStart = false
+		w.writeText(core)
+		w.out.WriteString(closing)
+		w.writeText(trail)
+	}
+}
+
+func (w *markdownWriter) writeText(text string) {
+	if text == "" {
+		return
+	}
+
+	escaped := markdownEscaper.Replace(text)
+
+	if w.atLineStart {
+		escaped = escapeLineStart(escaped)
+	}
+
+	w.out.WriteString(escaped)
+	w.atLineStart = false
+}
+
+// escapeLineStart escapes characters that would start a heading, quote,
+// list or rule when they appear at the beginning of a line.
+func escapeLineStart(text string) string {
+	if m := orderedMarkerRe.FindStringSubmatchIndex(text); m != nil {
+		return text[:m[3]] + `\` + text[m[4]:]
+	}
+
+	if strings.ContainsAny(text[:1], "#>-+=") {
+		return `\` + text
+	}
+
+	return text
+}
+
+// emphasisMarkers returns the opening and closing Markdown markers for a
+// style. Markdown has no underline syntax, so underlined text stays plain;
+// the rich text keeps the underline.
+func emphasisMarkers(style models.TextStyle) (open, closing string) {
+	if style.Strikethrough {
+		open, closing = open+"~~", "~~"+closing
+	}
+
+	if style.Bold {
+		open, closing = open+"**", "**"+closing
+	}
+
+	if style.Italic {
+		open, closing = open+"*", "*"+closing
+	}
+
+	return open, closing
+}
+
+func sameFormatting(a, b models.TextStyle) bool {
+	return a.Bold == b.Bold && a.Italic == b.Italic && a.Strikethrough == b.Strikethrough
+}
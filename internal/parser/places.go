@@ -0,0 +1,118 @@
+package parser
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+
+	"github.com/kpod13/journal2day1/internal/models"
+)
+
+// mapPlace extracts the place of a map grid item. The markup gives its
+// caption and may link to Apple Maps or carry the coordinates in data
+// attributes; the resource sidecar, when present, wins. It returns nil for a
+// map without a label or coordinates.
+func (p *AppleJournalParser) mapPlace(n *html.Node, id string) *models.MapPlace {
+	place := &models.MapPlace{Label: strings.Join(strings.Fields(getTextContent(n)), " ")}
+
+	markupPlace(n, place)
+
+	if meta, err := p.LoadResourceMeta(id); err == nil {
+		applySidecarPlace(place, meta)
+	}
+
+	if place.Label == "" && !place.HasCoordinates() {
+		return nil
+	}
+
+	return place
+}
+
+// markupPlace fills in the coordinates of a place from the first element of
+// the grid item that has data-latitude and data-longitude attributes or links
+// to Apple Maps, whose query may also name the place. It reports whether one
+// was found.
+func markupPlace(n *html.Node, place *models.MapPlace) bool {
+	if n.Type == html.ElementNode {
+		if lat, lon, ok := parseCoordinates(getAttr(n, "data-latitude"), getAttr(n, "data-longitude")); ok {
+			place.Latitude, place.Longitude = &lat, &lon
+
+			return true
+		}
+
+		if n.Data == "a" && mapsLinkPlace(getAttr(n, "href"), place) {
+			return true
+		}
+	}
+
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if markupPlace(c, place) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// mapsLinkPlace reads a link such as https://maps.apple.com/?ll=41.89,12.49&q=Colosseum.
+func mapsLinkPlace(href string, place *models.MapPlace) bool {
+	link, err := url.Parse(href)
+	if err != nil || link.Host != "maps.apple.com" {
+		return false
+	}
+
+	query := link.Query()
+
+	coordinates := query.Get("ll")
+	if coordinates == "" {
+		coordinates = query.Get("coordinate")
+	}
+
+	latText, lonText, _ := strings.Cut(coordinates, ",")
+
+	lat, lon, ok := parseCoordinates(latText, lonText)
+	if !ok {
+		return false
+	}
+
+	place.Latitude, place.Longitude = &lat, &lon
+
+	if label := strings.TrimSpace(query.Get("q")); label != "" && place.Label == "" {
+		place.Label = label
+	}
+
+	return true
+}
+
+// parseCoordinates parses a latitude and longitude in decimal degrees.
+func parseCoordinates(latText, lonText string) (lat, lon float64, ok bool) {
+	lat, latErr := strconv.ParseFloat(strings.TrimSpace(latText), 64)
+	lon, lonErr := strconv.ParseFloat(strings.TrimSpace(lonText), 64)
+
+	if latErr != nil || lonErr != nil || lat < -90 || lat > 90 || lon < -180 || lon > 180 {
+		return 0, 0, false
+	}
+
+	return lat, lon, true
+}
+
+// applySidecarPlace overrides the place with the name, coordinates and radius
+// from the map's resource sidecar.
+func applySidecarPlace(place *models.MapPlace, meta *models.AppleJournalResourceMeta) {
+	switch {
+	case meta.PlaceName != "":
+		place.Label = meta.PlaceName
+	case place.Label == "":
+		place.Label = meta.Locality
+	}
+
+	if meta.HasCoordinates() {
+		place.Latitude, place.Longitude = meta.Latitude, meta.Longitude
+	}
+
+	if meta.Radius > 0 {
+		place.Radius = meta.Radius
+	}
+}
@@ -0,0 +1,82 @@
+package parser_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/kpod13/journal2day1/internal/models"
+	"github.com/kpod13/journal2day1/internal/parser"
+)
+
+func TestParseEntryMapPlace(t *testing.T) {
+	t.Parallel()
+
+	latitude, longitude := 41.8902, 12.4922
+
+	tests := []struct {
+		name    string
+		item    string
+		sidecar string
+		want    *models.MapPlace
+	}{
+		{
+			name: "caption and data attributes",
+			item: `<div class="mapSnapshot" data-latitude="41.8902" data-longitude="12.4922"></div>
+<div class="caption"> Colosseum
+  Rome </div>`,
+			want: &models.MapPlace{Label: "Colosseum Rome", Latitude: &latitude, Longitude: &longitude},
+		},
+		{
+			name: "apple maps link",
+			item: `<a href="https://maps.apple.com/?ll=41.8902,12.4922&amp;q=Colosseum"></a>`,
+			want: &models.MapPlace{Label: "Colosseum", Latitude: &latitude, Longitude: &longitude},
+		},
+		{
+			name:    "sidecar wins",
+			item:    `<div data-latitude="1" data-longitude="2">Somewhere</div>`,
+			sidecar: `{"placeName": "Colosseum", "latitude": 41.8902, "longitude": 12.4922, "radius": 250}`,
+			want:    &models.MapPlace{Label: "Colosseum", Latitude: &latitude, Longitude: &longitude, Radius: 250},
+		},
+		{
+			name:    "sidecar locality names an unlabeled place",
+			sidecar: `{"locality": "Rome"}`,
+			want:    &models.MapPlace{Label: "Rome"},
+		},
+		{
+			name: "out of range coordinates are ignored",
+			item: `<div data-latitude="91" data-longitude="12"></div>`,
+		},
+		{
+			name: "no place",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			tmpDir := t.TempDir()
+			createDirs(t, tmpDir)
+
+			content := `<div class="pageHeader">Monday, 15 December 2025</div>
+<div class="assetGrid"><div id="MAP-UUID" class="gridItem assetType_genericMap">` + tt.item + `</div></div>`
+
+			entryPath := filepath.Join(tmpDir, "Entries", "2025-12-15_Map.html")
+			require.NoError(t, os.WriteFile(entryPath, []byte(content), 0o600))
+
+			if tt.sidecar != "" {
+				sidecarPath := filepath.Join(tmpDir, "Resources", "MAP-UUID.json")
+				require.NoError(t, os.WriteFile(sidecarPath, []byte(tt.sidecar), 0o600))
+			}
+
+			entry, err := parser.NewAppleJournalParser(tmpDir).ParseEntry(entryPath)
+			require.NoError(t, err)
+			require.Len(t, entry.Assets, 1)
+			require.Equal(t, "map", entry.Assets[0].Type)
+			require.Equal(t, tt.want, entry.Assets[0].Place)
+		})
+	}
+}
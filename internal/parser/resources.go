@@ -0,0 +1,176 @@
+package parser
+
+import (
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/kpod13/journal2day1/internal/models"
+)
+
+const sidecarExtension = ".json"
+
+// Asset types of media files.
+const (
+	assetTypePhoto = "photo"
+	assetTypeVideo = "video"
+	assetTypeAudio = "audio"
+)
+
+// ResourceCatalog indexes the Resources directory of an export by asset ID.
+// Resource files are named after the asset ID they belong to, e.g.
+// "<UUID>.heic" for the media and "<UUID>.json" for its metadata sidecar.
+type ResourceCatalog struct {
+	media    map[string][]string // sorted paths within the export
+	sidecars map[string]string
+}
+
+// newResourceCatalog reads the Resources directory once. A missing directory
+// yields an empty catalog, as entries without assets need no resources.
+func newResourceCatalog(fsys fs.FS) (*ResourceCatalog, error) {
+	c := &ResourceCatalog{
+		media:    make(map[string][]string),
+		sidecars: make(map[string]string),
+	}
+
+	files, err := fs.ReadDir(fsys, resourcesDir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return c, nil
+	}
+
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read resources directory")
+	}
+
+	for _, file := range files {
+		name := file.Name()
+		if file.IsDir() || strings.HasPrefix(name, ".") {
+			continue
+		}
+
+		ext := path.Ext(name)
+		id := strings.TrimSuffix(name, ext)
+		filePath := path.Join(resourcesDir, name)
+
+		if strings.EqualFold(ext, sidecarExtension) {
+			c.sidecars[id] = filePath
+
+			continue
+		}
+
+		// fs.ReadDir returns names sorted, so media paths stay sorted.
+		c.media[id] = append(c.media[id], filePath)
+	}
+
+	return c, nil
+}
+
+// MediaFiles returns the paths of all media files of an asset.
+func (c *ResourceCatalog) MediaFiles(id string) []string {
+	return c.media[id]
+}
+
+// MediaFile returns the path of the first media file of an asset, or an
+// empty string when the asset has none.
+func (c *ResourceCatalog) MediaFile(id string) string {
+	if files := c.media[id]; len(files) > 0 {
+		return files[0]
+	}
+
+	return ""
+}
+
+// AssetFile returns the path of the media file that fits an asset type, e.g.
+// the video of a video asset with a poster image next to it. It falls back
+// to the first media file, or an empty string when the asset has none.
+func (c *ResourceCatalog) AssetFile(id, assetType string) string {
+	for _, file := range c.media[id] {
+		if fileAssetType(file) == assetType {
+			return file
+		}
+	}
+
+	return c.MediaFile(id)
+}
+
+// LivePhotoFiles returns the paths of the still image and the motion video
+// of a Live Photo, leaving empty the parts the asset has no file for.
+func (c *ResourceCatalog) LivePhotoFiles(id string) (still, motion string) {
+	for _, file := range c.media[id] {
+		switch {
+		case fileAssetType(file) == assetTypeVideo:
+			if motion == "" {
+				motion = file
+			}
+		case still == "":
+			still = file
+		}
+	}
+
+	return still, motion
+}
+
+// fileAssetType returns the asset type a media file holds by its extension.
+// Unknown extensions are taken for photos.
+func fileAssetType(name string) string {
+	switch strings.ToLower(path.Ext(name)) {
+	case ".mov", ".mp4", ".m4v", ".avi":
+		return assetTypeVideo
+	case ".m4a", ".aac", ".mp3", ".wav":
+		return assetTypeAudio
+	}
+
+	return assetTypePhoto
+}
+
+// Sidecar returns the path of an asset's metadata file, or an empty string.
+func (c *ResourceCatalog) Sidecar(id string) string {
+	return c.sidecars[id]
+}
+
+// IDs returns the sorted IDs of all assets with resource files.
+func (c *ResourceCatalog) IDs() []string {
+	seen := make(map[string]bool, len(c.media)+len(c.sidecars))
+
+	for id := range c.media {
+		seen[id] = true
+	}
+
+	for id := range c.sidecars {
+		seen[id] = true
+	}
+
+	ids := make([]string, 0, len(seen))
+
+	for id := range seen {
+		ids = append(ids, id)
+	}
+
+	sort.Strings(ids)
+
+	return ids
+}
+
+// Orphans returns the sorted IDs of resources no entry refers to.
+func (c *ResourceCatalog) Orphans(entries []models.AppleJournalEntry) []string {
+	referenced := make(map[string]bool)
+
+	for i := range entries {
+		for _, asset := range entries[i].Assets {
+			referenced[asset.ID] = true
+		}
+	}
+
+	var orphans []string
+
+	for _, id := range c.IDs() {
+		if !referenced[id] {
+			orphans = append(orphans, id)
+		}
+	}
+
+	return orphans
+}
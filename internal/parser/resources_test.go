@@ -0,0 +1,126 @@
+package parser_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/kpod13/journal2day1/internal/models"
+	"github.com/kpod13/journal2day1/internal/parser"
+)
+
+func setupResourceCatalogTestData(t *testing.T, dir string) {
+	t.Helper()
+
+	resourcesDir := filepath.Join(dir, "Resources")
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "Entries"), 0o750))
+	require.NoError(t, os.MkdirAll(resourcesDir, 0o750))
+
+	for _, name := range []string{
+		"ABC.heic", "ABC.json", "ABC.mov",
+		"ABCDEF.jpg", "ABCDEF.json",
+		"ORPHAN.png",
+		".DS_Store",
+	} {
+		require.NoError(t, os.WriteFile(filepath.Join(resourcesDir, name), []byte(name), 0o600))
+	}
+}
+
+func TestResourceCatalogLookups(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	setupResourceCatalogTestData(t, tmpDir)
+
+	catalog, err := parser.NewAppleJournalParser(tmpDir).Resources()
+	require.NoError(t, err)
+
+	require.Equal(t, []string{"Resources/ABC.heic", "Resources/ABC.mov"}, catalog.MediaFiles("ABC"))
+	require.Equal(t, "Resources/ABC.heic", catalog.MediaFile("ABC"))
+	require.Equal(t, "Resources/ABCDEF.jpg", catalog.MediaFile("ABCDEF"))
+	require.Equal(t, "Resources/ABC.json", catalog.Sidecar("ABC"))
+
+	still, motion := catalog.LivePhotoFiles("ABC")
+	require.Equal(t, "Resources/ABC.heic", still)
+	require.Equal(t, "Resources/ABC.mov", motion)
+
+	still, motion = catalog.LivePhotoFiles("ABCDEF")
+	require.Equal(t, "Resources/ABCDEF.jpg", still)
+	require.Empty(t, motion)
+	require.Empty(t, catalog.Sidecar("ORPHAN"))
+	require.Empty(t, catalog.MediaFile("AB"))
+	require.Equal(t, []string{"ABC", "ABCDEF", "ORPHAN"}, catalog.IDs())
+}
+
+func TestResourceCatalogOrphans(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	setupResourceCatalogTestData(t, tmpDir)
+
+	catalog, err := parser.NewAppleJournalParser(tmpDir).Resources()
+	require.NoError(t, err)
+
+	entries := []models.AppleJournalEntry{
+		{Assets: []models.AppleJournalAsset{{ID: "ABC"}}},
+		{Assets: []models.AppleJournalAsset{{ID: "ABCDEF"}}},
+	}
+
+	require.Equal(t, []string{"ORPHAN"}, catalog.Orphans(entries))
+}
+
+func TestResourceCatalogMissingDirectory(t *testing.T) {
+	t.Parallel()
+
+	catalog, err := parser.NewAppleJournalParser(t.TempDir()).Resources()
+
+	require.NoError(t, err)
+	require.Empty(t, catalog.IDs())
+}
+
+func TestAssetFilePathExactMatch(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	setupResourceCatalogTestData(t, tmpDir)
+
+	p := parser.NewAppleJournalParser(tmpDir)
+
+	require.Equal(t, "Resources/ABCDEF.jpg", p.AssetFilePath(&models.AppleJournalAsset{ID: "ABCDEF", Type: "photo"}))
+	require.Empty(t, p.AssetFilePath(&models.AppleJournalAsset{ID: "ABCD", Type: "photo"}))
+}
+
+func TestAssetFilePathByType(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	setupResourceCatalogTestData(t, tmpDir)
+
+	p := parser.NewAppleJournalParser(tmpDir)
+
+	testCases := []struct {
+		name  string
+		asset models.AppleJournalAsset
+		want  string
+	}{
+		{name: "video with a poster", asset: models.AppleJournalAsset{ID: "ABC", Type: "video"}, want: "Resources/ABC.mov"},
+		{name: "photo", asset: models.AppleJournalAsset{ID: "ABC", Type: "photo"}, want: "Resources/ABC.heic"},
+		{
+			name:  "grid item source wins",
+			asset: models.AppleJournalAsset{ID: "ABC", Type: "photo", FilePath: "../Resources/ABC.mov"},
+			want:  "Resources/ABC.mov",
+		},
+		{
+			name:  "missing grid item source",
+			asset: models.AppleJournalAsset{ID: "ABC", Type: "video", FilePath: "../Resources/ABC.mp4"},
+			want:  "Resources/ABC.mov",
+		},
+		{name: "no file of the type", asset: models.AppleJournalAsset{ID: "ABCDEF", Type: "video"}, want: "Resources/ABCDEF.jpg"},
+	}
+
+	for _, tc := range testCases {
+		require.Equal(t, tc.want, p.AssetFilePath(&tc.asset), tc.name)
+	}
+}
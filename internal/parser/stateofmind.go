@@ -0,0 +1,103 @@
+package parser
+
+import (
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+
+	"github.com/kpod13/journal2day1/internal/models"
+)
+
+// valenceClasses are the classes of State of Mind valence, from very
+// unpleasant to very pleasant. They split the valence range into equal parts.
+var valenceClasses = []string{
+	"very-unpleasant",
+	"unpleasant",
+	"slightly-unpleasant",
+	"neutral",
+	"slightly-pleasant",
+	"pleasant",
+	"very-pleasant",
+}
+
+// stateOfMind extracts the mood of a State of Mind grid item from its data
+// attributes, or from a caption naming the valence class; the resource
+// sidecar, when present, wins. It returns nil when neither describes a mood.
+func (p *AppleJournalParser) stateOfMind(n *html.Node, id string) *models.StateOfMind {
+	mood := &models.StateOfMind{
+		Kind:           slug(findAttr(n, "data-kind")),
+		Classification: slug(findAttr(n, "data-valence-classification")),
+		Labels:         slugList(strings.Split(findAttr(n, "data-labels"), ",")),
+		Associations:   slugList(strings.Split(findAttr(n, "data-associations"), ",")),
+	}
+
+	if valence, err := strconv.ParseFloat(findAttr(n, "data-valence"), 64); err == nil {
+		mood.Valence = &valence
+	}
+
+	if caption := slug(getTextContent(n)); mood.Classification == "" && slices.Contains(valenceClasses, caption) {
+		mood.Classification = caption
+	}
+
+	if meta, err := p.LoadResourceMeta(id); err == nil {
+		applySidecarMood(mood, meta)
+	}
+
+	if mood.Classification == "" && mood.Valence != nil {
+		mood.Classification = valenceClass(*mood.Valence)
+	}
+
+	if mood.Classification == "" && len(mood.Labels) == 0 && len(mood.Associations) == 0 {
+		return nil
+	}
+
+	return mood
+}
+
+// applySidecarMood overrides the mood with the fields of its resource
+// sidecar.
+func applySidecarMood(mood *models.StateOfMind, meta *models.AppleJournalResourceMeta) {
+	if meta.StateOfMindKind != "" {
+		mood.Kind = slug(meta.StateOfMindKind)
+	}
+
+	if meta.Valence != nil {
+		mood.Valence = meta.Valence
+		mood.Classification = ""
+	}
+
+	if meta.Classification != "" {
+		mood.Classification = slug(meta.Classification)
+	}
+
+	if labels := slugList(meta.Labels); len(labels) > 0 {
+		mood.Labels = labels
+	}
+
+	if associations := slugList(meta.Associations); len(associations) > 0 {
+		mood.Associations = associations
+	}
+}
+
+// valenceClass returns the class of a valence between -1 and 1.
+func valenceClass(valence float64) string {
+	index := int(math.Floor((valence + 1) / 2 * float64(len(valenceClasses))))
+
+	return valenceClasses[max(0, min(index, len(valenceClasses)-1))]
+}
+
+// slugList returns the non-empty slugs of the names.
+func slugList(names []string) []string {
+	var slugs []string
+
+	for _, name := range names {
+		if s := slug(name); s != "" {
+			slugs = append(slugs, s)
+		}
+	}
+
+	return slugs
+}
@@ -0,0 +1,90 @@
+package parser_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/kpod13/journal2day1/internal/models"
+	"github.com/kpod13/journal2day1/internal/parser"
+)
+
+func TestParseEntryStateOfMind(t *testing.T) {
+	t.Parallel()
+
+	valence, negative, slight := 0.6, -0.75, 0.1
+
+	tests := []struct {
+		name    string
+		item    string
+		sidecar string
+		want    *models.StateOfMind
+	}{
+		{
+			name: "data attributes",
+			item: `<div data-kind="Daily Mood" data-valence="0.6"
+data-labels="Happy, Grateful" data-associations="Family,Work"></div>`,
+			want: &models.StateOfMind{
+				Kind:           "daily-mood",
+				Valence:        &valence,
+				Classification: "pleasant",
+				Labels:         []string{"happy", "grateful"},
+				Associations:   []string{"family", "work"},
+			},
+		},
+		{
+			name: "caption",
+			item: `<div class="caption">Slightly Pleasant</div>`,
+			want: &models.StateOfMind{Classification: "slightly-pleasant"},
+		},
+		{
+			name:    "sidecar wins",
+			item:    `<div data-valence-classification="neutral" data-labels="calm"></div>`,
+			sidecar: `{"kind": "momentaryEmotion", "valence": -0.75, "labels": ["Stressed"], "associations": ["work"]}`,
+			want: &models.StateOfMind{
+				Kind:           "momentary-emotion",
+				Valence:        &negative,
+				Classification: "very-unpleasant",
+				Labels:         []string{"stressed"},
+				Associations:   []string{"work"},
+			},
+		},
+		{
+			name:    "sidecar classification",
+			sidecar: `{"valence": 0.1, "valenceClassification": "Slightly Pleasant"}`,
+			want:    &models.StateOfMind{Valence: &slight, Classification: "slightly-pleasant"},
+		},
+		{
+			name: "no data",
+			item: `<div class="caption">Something</div>`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			tmpDir := t.TempDir()
+			createDirs(t, tmpDir)
+
+			content := `<div class="pageHeader">Monday, 15 December 2025</div>
+<div class="assetGrid"><div id="MOOD-UUID" class="gridItem assetType_stateOfMind">` + tt.item + `</div></div>`
+
+			entryPath := filepath.Join(tmpDir, "Entries", "2025-12-15_Mood.html")
+			require.NoError(t, os.WriteFile(entryPath, []byte(content), 0o600))
+
+			if tt.sidecar != "" {
+				sidecarPath := filepath.Join(tmpDir, "Resources", "MOOD-UUID.json")
+				require.NoError(t, os.WriteFile(sidecarPath, []byte(tt.sidecar), 0o600))
+			}
+
+			entry, err := parser.NewAppleJournalParser(tmpDir).ParseEntry(entryPath)
+			require.NoError(t, err)
+			require.Len(t, entry.Assets, 1)
+			require.Equal(t, "stateOfMind", entry.Assets[0].Type)
+			require.Equal(t, tt.want, entry.Assets[0].Mood)
+		})
+	}
+}
@@ -0,0 +1,452 @@
+package parser
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// maxIdleCursors bounds the decompression streams kept open for reuse.
+const maxIdleCursors = 4
+
+// tarFS serves the members of a tar.gz archive without unpacking it. Entry
+// pages and metadata sidecars are held in memory. Other members, the media,
+// are decompressed on demand from their offset in the archive; gzip streams
+// cannot be read at random offsets, so a stream left before the member by an
+// earlier read is resumed where possible instead of starting over.
+type tarFS struct {
+	archivePath string
+	members     map[string]*tarMember // by path within the archive
+
+	mu   sync.Mutex
+	idle []*tarCursor
+}
+
+// tarMember is a file or directory of the archive.
+type tarMember struct {
+	name     string // path within the archive
+	info     fs.FileInfo
+	children map[string]bool // names within a directory
+	data     []byte          // of a file held in memory
+	inMemory bool
+	offset   int64 // of the file data within the decompressed archive
+}
+
+// indexTarGz reads the archive once, recording where each member starts.
+func indexTarGz(archivePath string) (*tarFS, error) {
+	file, err := os.Open(filepath.Clean(archivePath))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open archive")
+	}
+
+	defer func() { _ = file.Close() }() //nolint:errcheck // read-only file close errors are not critical
+
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read gzip stream")
+	}
+
+	counter := &countingReader{r: gz}
+	tr := tar.NewReader(counter)
+	t := &tarFS{
+		archivePath: archivePath,
+		members:     map[string]*tarMember{".": newTarDir(".")},
+	}
+
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return t, nil
+		}
+
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read tar archive")
+		}
+
+		if err := t.add(tr, header, counter.n); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// add records a member whose data starts at the given offset.
+func (t *tarFS) add(r io.Reader, header *tar.Header, offset int64) error {
+	name := path.Clean(strings.TrimPrefix(header.Name, "./"))
+	if name == "." {
+		return nil
+	}
+
+	if !fs.ValidPath(name) {
+		return errors.Errorf("archive entry %q escapes the export", header.Name)
+	}
+
+	switch header.Typeflag {
+	case tar.TypeDir:
+		t.dir(name).info = header.FileInfo()
+	case tar.TypeReg:
+		member := &tarMember{name: name, info: header.FileInfo(), offset: offset, inMemory: keepInMemory(name)}
+
+		if member.inMemory {
+			data, err := io.ReadAll(r)
+			if err != nil {
+				return errors.Wrapf(err, "failed to read %s", name)
+			}
+
+			member.data = data
+		}
+
+		t.members[name] = member
+		t.dir(path.Dir(name)).children[path.Base(name)] = true
+	}
+
+	// Links and special files are not part of Apple Journal exports.
+	return nil
+}
+
+// dir returns the directory member of a path, creating it and its parents as
+// archives need not list directories.
+func (t *tarFS) dir(name string) *tarMember {
+	if member, ok := t.members[name]; ok && member.children != nil {
+		return member
+	}
+
+	member := newTarDir(name)
+	t.members[name] = member
+	t.dir(path.Dir(name)).children[path.Base(name)] = true
+
+	return member
+}
+
+func newTarDir(name string) *tarMember {
+	return &tarMember{name: name, info: dirInfo(path.Base(name)), children: make(map[string]bool)}
+}
+
+// keepInMemory reports whether a member is small text read as a whole: an
+// entry page or a metadata sidecar.
+func keepInMemory(name string) bool {
+	switch strings.ToLower(path.Ext(name)) {
+	case ".html", ".htm", sidecarExtension:
+		return true
+	}
+
+	return false
+}
+
+// Open implements fs.FS.
+func (t *tarFS) Open(name string) (fs.File, error) {
+	member, err := t.member("open", name)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case member.children != nil:
+		return &tarDirFile{info: member.info, entries: t.entries(member)}, nil
+	case member.inMemory:
+		return &tarMemFile{info: member.info, Reader: bytes.NewReader(member.data)}, nil
+	}
+
+	return &tarFile{fsys: t, member: member}, nil
+}
+
+// ReadDir implements fs.ReadDirFS.
+func (t *tarFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	member, err := t.member("readdir", name)
+	if err != nil {
+		return nil, err
+	}
+
+	if member.children == nil {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: errNotDir}
+	}
+
+	return t.entries(member), nil
+}
+
+func (t *tarFS) member(op, name string) (*tarMember, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: op, Path: name, Err: fs.ErrInvalid}
+	}
+
+	member, ok := t.members[name]
+	if !ok {
+		return nil, &fs.PathError{Op: op, Path: name, Err: fs.ErrNotExist}
+	}
+
+	return member, nil
+}
+
+// Close closes the idle decompression streams.
+func (t *tarFS) Close() error {
+	t.mu.Lock()
+	idle := t.idle
+	t.idle = nil
+	t.mu.Unlock()
+
+	var firstErr error
+
+	for _, cursor := range idle {
+		if err := cursor.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	return firstErr
+}
+
+// cursorAt returns a decompression stream positioned at the offset, resuming
+// the idle stream closest before it or starting a new one.
+func (t *tarFS) cursorAt(offset int64) (*tarCursor, error) {
+	t.mu.Lock()
+
+	best := -1
+
+	for i, cursor := range t.idle {
+		if cursor.pos <= offset && (best < 0 || cursor.pos > t.idle[best].pos) {
+			best = i
+		}
+	}
+
+	var cursor *tarCursor
+
+	if best >= 0 {
+		cursor = t.idle[best]
+		t.idle = slices.Delete(t.idle, best, best+1)
+	}
+
+	t.mu.Unlock()
+
+	if cursor == nil {
+		var err error
+
+		if cursor, err = openTarCursor(t.archivePath); err != nil {
+			return nil, err
+		}
+	}
+
+	if _, err := io.CopyN(io.Discard, cursor, offset-cursor.pos); err != nil {
+		_ = cursor.Close() //nolint:errcheck // the read error is more relevant
+
+		return nil, errors.Wrap(err, "failed to reach archive member")
+	}
+
+	return cursor, nil
+}
+
+// release keeps a stream for reuse, closing the furthest one beyond the limit.
+func (t *tarFS) release(cursor *tarCursor) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.idle = append(t.idle, cursor)
+
+	if len(t.idle) > maxIdleCursors {
+		furthest := 0
+
+		for i := range t.idle {
+			if t.idle[i].pos > t.idle[furthest].pos {
+				furthest = i
+			}
+		}
+
+		_ = t.idle[furthest].Close() //nolint:errcheck // read-only file close errors are not critical
+		t.idle = slices.Delete(t.idle, furthest, furthest+1)
+	}
+}
+
+// entries returns the sorted entries of a directory.
+func (t *tarFS) entries(dir *tarMember) []fs.DirEntry {
+	names := make([]string, 0, len(dir.children))
+
+	for name := range dir.children {
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+
+	entries := make([]fs.DirEntry, 0, len(names))
+
+	for _, name := range names {
+		entries = append(entries, fs.FileInfoToDirEntry(t.members[path.Join(dir.name, name)].info))
+	}
+
+	return entries
+}
+
+// tarCursor is a decompression stream over the archive at a known position.
+type tarCursor struct {
+	file *os.File
+	gz   *gzip.Reader
+	pos  int64
+}
+
+func openTarCursor(archivePath string) (*tarCursor, error) {
+	file, err := os.Open(filepath.Clean(archivePath))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open archive")
+	}
+
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		_ = file.Close() //nolint:errcheck // the gzip error is more relevant
+
+		return nil, errors.Wrap(err, "failed to read gzip stream")
+	}
+
+	return &tarCursor{file: file, gz: gz}, nil
+}
+
+func (c *tarCursor) Read(b []byte) (int, error) {
+	n, err := c.gz.Read(b)
+	c.pos += int64(n)
+
+	return n, err
+}
+
+func (c *tarCursor) Close() error {
+	return errors.Wrap(c.file.Close(), "failed to close archive")
+}
+
+// tarFile streams the data of a media member, starting to decompress on the
+// first read so that Stat alone stays cheap.
+type tarFile struct {
+	fsys   *tarFS
+	member *tarMember
+	cursor *tarCursor
+	read   int64
+	failed bool
+}
+
+func (f *tarFile) Stat() (fs.FileInfo, error) { return f.member.info, nil }
+
+func (f *tarFile) Read(b []byte) (int, error) {
+	remaining := f.member.info.Size() - f.read
+	if remaining <= 0 {
+		return 0, io.EOF
+	}
+
+	if f.cursor == nil {
+		cursor, err := f.fsys.cursorAt(f.member.offset + f.read)
+		if err != nil {
+			return 0, err
+		}
+
+		f.cursor = cursor
+	}
+
+	if int64(len(b)) > remaining {
+		b = b[:remaining]
+	}
+
+	n, err := f.cursor.Read(b)
+	f.read += int64(n)
+
+	if err != nil {
+		f.failed = true
+
+		if errors.Is(err, io.EOF) {
+			return n, io.ErrUnexpectedEOF
+		}
+	}
+
+	return n, err
+}
+
+func (f *tarFile) Close() error {
+	cursor := f.cursor
+	f.cursor = nil
+
+	if cursor == nil {
+		return nil
+	}
+
+	if f.failed {
+		return cursor.Close()
+	}
+
+	f.fsys.release(cursor)
+
+	return nil
+}
+
+// tarMemFile is a member held in memory.
+type tarMemFile struct {
+	*bytes.Reader
+
+	info fs.FileInfo
+}
+
+func (f *tarMemFile) Stat() (fs.FileInfo, error) { return f.info, nil }
+func (f *tarMemFile) Close() error               { return nil }
+
+// tarDirFile is an opened directory.
+type tarDirFile struct {
+	info    fs.FileInfo
+	entries []fs.DirEntry
+	read    int
+}
+
+var (
+	errNotDir = errors.New("not a directory")
+	errIsDir  = errors.New("is a directory")
+)
+
+func (d *tarDirFile) Stat() (fs.FileInfo, error) { return d.info, nil }
+func (d *tarDirFile) Close() error               { return nil }
+
+func (d *tarDirFile) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.info.Name(), Err: errIsDir}
+}
+
+func (d *tarDirFile) ReadDir(n int) ([]fs.DirEntry, error) {
+	rest := d.entries[d.read:]
+
+	if n <= 0 {
+		d.read = len(d.entries)
+
+		return rest, nil
+	}
+
+	if len(rest) == 0 {
+		return nil, io.EOF
+	}
+
+	n = min(n, len(rest))
+	d.read += n
+
+	return rest[:n], nil
+}
+
+// dirInfo describes a directory the archive does not list itself.
+type dirInfo string
+
+func (d dirInfo) Name() string       { return string(d) }
+func (d dirInfo) Size() int64        { return 0 }
+func (d dirInfo) Mode() fs.FileMode  { return fs.ModeDir | 0o555 }
+func (d dirInfo) ModTime() time.Time { return time.Time{} }
+func (d dirInfo) IsDir() bool        { return true }
+func (d dirInfo) Sys() any           { return nil }
+
+// countingReader counts the bytes read through it.
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(b []byte) (int, error) {
+	n, err := c.r.Read(b)
+	c.n += int64(n)
+
+	return n, err
+}
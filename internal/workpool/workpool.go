@@ -0,0 +1,36 @@
+// Package workpool runs indexed work items on a bounded number of goroutines.
+package workpool
+
+import "sync"
+
+// ForEach calls fn for every index in [0, n) using at most workers
+// goroutines and returns when all calls have finished. Callers keep output
+// deterministic by storing results at the index they were given.
+func ForEach(workers, n int, fn func(i int)) {
+	if workers < 1 {
+		workers = 1
+	}
+
+	if workers > n {
+		workers = n
+	}
+
+	indexes := make(chan int)
+
+	var wg sync.WaitGroup
+
+	for range workers {
+		wg.Go(func() {
+			for i := range indexes {
+				fn(i)
+			}
+		})
+	}
+
+	for i := range n {
+		indexes <- i
+	}
+
+	close(indexes)
+	wg.Wait()
+}
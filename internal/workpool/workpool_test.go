@@ -0,0 +1,65 @@
+package workpool_test
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/kpod13/journal2day1/internal/workpool"
+)
+
+func TestForEach(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name    string
+		workers int
+		n       int
+	}{
+		{name: "single worker", workers: 1, n: 10},
+		{name: "several workers", workers: 4, n: 100},
+		{name: "more workers than items", workers: 8, n: 3},
+		{name: "invalid worker count", workers: 0, n: 5},
+		{name: "no items", workers: 4, n: 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			results := make([]int, tc.n)
+
+			workpool.ForEach(tc.workers, tc.n, func(i int) {
+				results[i] = i * i
+			})
+
+			for i, got := range results {
+				require.Equal(t, i*i, got)
+			}
+		})
+	}
+}
+
+func TestForEachBoundsConcurrency(t *testing.T) {
+	t.Parallel()
+
+	const workers = 3
+
+	var running, peak atomic.Int32
+
+	workpool.ForEach(workers, 30, func(int) {
+		current := running.Add(1)
+
+		for old := peak.Load(); current > old && !peak.CompareAndSwap(old, current); old = peak.Load() {
+			// Retry until the peak is at least the current count.
+		}
+
+		time.Sleep(time.Millisecond)
+		running.Add(-1)
+	})
+
+	require.LessOrEqual(t, peak.Load(), int32(workers))
+	require.Positive(t, peak.Load())
+}